@@ -45,6 +45,20 @@ func AppendError(errors ...error) AggregateError {
 	return a
 }
 
+// newAggregateError creates an aggregate error of the non nil errors. Unlike AppendError it never
+// appends to an AggregateError in the list, the errors of other tasks are not modified.
+func newAggregateError(errors ...error) AggregateError {
+	a := &aggregateError{
+		errors: []error{},
+	}
+	for _, e := range errors {
+		if e != nil {
+			a.errors = append(a.errors, e)
+		}
+	}
+	return a
+}
+
 func (err *aggregateError) Append(errors ...error) {
 	err.errors = append(err.errors, errors...)
 }
@@ -52,7 +66,7 @@ func (err *aggregateError) Append(errors ...error) {
 func (err *aggregateError) Error() string {
 	outStr := ""
 	for _, e := range err.errors {
-		outStr = fmt.Sprintln(e.Error())
+		outStr += fmt.Sprintln(e.Error())
 	}
 	return outStr
 }
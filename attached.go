@@ -0,0 +1,87 @@
+package task
+
+import "context"
+
+// currentTaskKey is the context key of the task whose delegate is running
+type currentTaskKey struct{}
+
+// AttachedToParent attaches the task to the task whose delegate is running. The parent is found in the
+// task context, so the context passed to the delegate must be supplied with WithContext. The parent
+// completes only after all attached children complete and faults of the children are aggregated into
+// the error of the parent. The option has no effect if the context does not belong to a running delegate.
+func AttachedToParent() RunOption {
+	return func(t *task) {
+		t.attachToParent = true
+	}
+}
+
+// withCurrentTask returns a context that identifies the task as the parent of attached children
+func withCurrentTask(ctx context.Context, t *task) context.Context {
+	return context.WithValue(ctx, currentTaskKey{}, t)
+}
+
+// attachToCurrent attaches the task to the parent found in the task context
+func (t *task) attachToCurrent() {
+	parent, ok := t.context.Value(currentTaskKey{}).(*task)
+	if !ok || parent == t {
+		return
+	}
+	parent.attach(t)
+}
+
+// attach registers the child with the task if the delegate of the task is still running
+func (t *task) attach(child *task) {
+	t.mutex.Lock()
+	if t.delegateDone {
+		t.mutex.Unlock()
+		return
+	}
+	t.children++
+	t.mutex.Unlock()
+
+	// the child is not started yet so the subscription can not miss the completion
+	child.Subscribe(NewObserver(nil, func() {
+		t.childCompleted(child)
+	}, nil, nil))
+}
+
+// childCompleted records a fault of the child and runs the pending transition after the last child completes
+func (t *task) childCompleted(child Task) {
+	var err error
+	if child.IsFaulted() {
+		err = child.Error()
+	}
+
+	t.mutex.Lock()
+	if err != nil {
+		t.childErrors = append(t.childErrors, err)
+	}
+	t.children--
+	var transition func()
+	if t.children == 0 {
+		transition = t.pendingTransition
+		t.pendingTransition = nil
+	}
+	t.mutex.Unlock()
+
+	if transition != nil {
+		transition()
+	}
+}
+
+// completeWithChildren runs the transition once all attached children complete
+func (t *task) completeWithChildren(transition func()) {
+	t.mutex.Lock()
+	t.delegateDone = true
+	if t.children > 0 {
+		t.pendingTransition = transition
+		changed := t.changeStatus(StatusWaitingForChildren)
+		t.mutex.Unlock()
+		if changed {
+			t.tracker.NotifyStatus(StatusWaitingForChildren)
+		}
+		return
+	}
+	t.mutex.Unlock()
+	transition()
+}
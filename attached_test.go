@@ -0,0 +1,119 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("AttachedToParent", func() {
+	It("waits for attached children", func() {
+		release := make(chan struct{})
+		var childDone int32
+		parent := task.RunContextErrAction(func(ctx context.Context) error {
+			task.RunAction(func() {
+				<-release
+				atomic.StoreInt32(&childDone, 1)
+			}, task.WithContext(ctx), task.AttachedToParent())
+			return nil
+		})
+		Consistently(parent.IsCompleted, "50ms").Should(BeFalse())
+		close(release)
+		Expect(parent.Wait()).To(BeNil())
+		Expect(atomic.LoadInt32(&childDone)).To(Equal(int32(1)))
+		Expect(parent.IsSuccess()).To(BeTrue())
+	})
+	It("aggregates child faults", func() {
+		parent := task.RunContextErrAction(func(ctx context.Context) error {
+			task.RunErrAction(func() error {
+				return fmt.Errorf("child one")
+			}, task.WithContext(ctx), task.AttachedToParent())
+			task.RunErrAction(func() error {
+				return fmt.Errorf("child two")
+			}, task.WithContext(ctx), task.AttachedToParent())
+			return nil
+		})
+		err := parent.Wait()
+		Expect(parent.IsFaulted()).To(BeTrue())
+		aggregate, ok := err.(task.AggregateError)
+		Expect(ok).To(BeTrue())
+		Expect(len(aggregate.Errors())).To(Equal(2))
+	})
+	It("aggregates parent and child faults", func() {
+		parent := task.RunContextErrAction(func(ctx context.Context) error {
+			task.RunErrAction(func() error {
+				return fmt.Errorf("child")
+			}, task.WithContext(ctx), task.AttachedToParent())
+			return fmt.Errorf("parent")
+		})
+		err := parent.Wait()
+		aggregate, ok := err.(task.AggregateError)
+		Expect(ok).To(BeTrue())
+		Expect(len(aggregate.Errors())).To(Equal(2))
+		Expect(aggregate.Errors()[0].Error()).To(Equal("parent"))
+	})
+	It("does not modify an aggregate returned by the parent", func() {
+		when := task.WhenAll(task.FromError(fmt.Errorf("one")), task.FromError(fmt.Errorf("two")))
+		parent := task.RunContextErrAction(func(ctx context.Context) error {
+			task.RunErrAction(func() error {
+				return fmt.Errorf("child")
+			}, task.WithContext(ctx), task.AttachedToParent())
+			return when.Wait()
+		})
+		err := parent.Wait()
+		aggregate, ok := err.(task.AggregateError)
+		Expect(ok).To(BeTrue())
+		Expect(len(aggregate.Errors())).To(Equal(2))
+		Expect(aggregate.Errors()[0]).To(Equal(when.Error()))
+		Expect(len(when.Error().(task.AggregateError).Errors())).To(Equal(2))
+	})
+	It("waits for grandchildren", func() {
+		release := make(chan struct{})
+		parent := task.RunContextErrAction(func(ctx context.Context) error {
+			task.RunContextErrAction(func(ctx context.Context) error {
+				task.RunAction(func() {
+					<-release
+				}, task.WithContext(ctx), task.AttachedToParent())
+				return nil
+			}, task.WithContext(ctx), task.AttachedToParent())
+			return nil
+		})
+		Consistently(parent.IsCompleted, "50ms").Should(BeFalse())
+		close(release)
+		Expect(parent.Wait()).To(BeNil())
+	})
+	It("waits for children when canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		parent := task.RunContextErrAction(func(ctx context.Context) error {
+			task.RunAction(func() {
+				<-release
+			}, task.WithContext(ctx), task.AttachedToParent())
+			return nil
+		}, task.WithContext(ctx))
+		Eventually(parent.Status).Should(Equal(task.StatusWaitingForChildren))
+		cancel()
+		Consistently(parent.Status, "50ms").Should(Equal(task.StatusWaitingForChildren))
+		close(release)
+		Eventually(parent.Status).Should(Equal(task.StatusSuccess))
+	})
+	It("does not wait for detached children", func() {
+		release := make(chan struct{})
+		defer close(release)
+		parent := task.RunContextErrAction(func(ctx context.Context) error {
+			task.RunAction(func() {
+				<-release
+			})
+			return nil
+		})
+		Expect(parent.Wait()).To(BeNil())
+	})
+	It("ignores tasks created outside of a delegate", func() {
+		t := task.RunAction(func() {}, task.AttachedToParent())
+		Expect(t.Wait()).To(BeNil())
+	})
+})
@@ -0,0 +1,265 @@
+package task
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// CancellationSource signals cancellation to the tasks that observe it.
+// Tasks observe a cancellation source with the WithCancellation run option.
+type CancellationSource interface {
+	// Cancel communicates a request for cancellation and runs registered callbacks
+	Cancel()
+	// CancelAfter schedules a cancel operation after the given duration
+	CancelAfter(time.Duration)
+	// IsCancellationRequested returns true if cancel was called
+	IsCancellationRequested() bool
+	// Context returns a context that is canceled when the source is canceled
+	Context() context.Context
+	// Register adds a callback that is called when the source is canceled. If the source is
+	// already canceled the callback is called immediately. Closing the registration removes the callback.
+	Register(func()) io.Closer
+	// Close releases any timers and linked registrations held by the source. It does not cancel the source.
+	io.Closer
+}
+
+type CancellationOption func(s *cancellationSource)
+
+// WithCancellationClock sets the clock used by CancelAfter. The default is the DefaultClock.
+func WithCancellationClock(clock Clock) CancellationOption {
+	return func(s *cancellationSource) {
+		s.clock = clock
+	}
+}
+
+type cancellationSource struct {
+	mutex     sync.Mutex
+	context   context.Context
+	cancel    context.CancelFunc
+	canceled  bool
+	callbacks []*registration
+	clock     Clock
+	timer     Timer
+	links     []io.Closer
+}
+
+type registration struct {
+	source   *cancellationSource
+	callback func()
+}
+
+// NewCancellationSource creates a new cancellation source
+func NewCancellationSource(options ...CancellationOption) CancellationSource {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &cancellationSource{
+		context:   ctx,
+		cancel:    cancel,
+		callbacks: []*registration{},
+		clock:     DefaultClock(),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+// NewLinkedCancellationSource creates a cancellation source that is canceled when any of the given sources are canceled
+func NewLinkedCancellationSource(sources ...CancellationSource) CancellationSource {
+	linked := NewCancellationSource().(*cancellationSource)
+	links := []io.Closer{}
+	for _, source := range sources {
+		links = append(links, source.Register(linked.Cancel))
+	}
+	linked.mutex.Lock()
+	defer linked.mutex.Unlock()
+	linked.links = links
+	return linked
+}
+
+func (s *cancellationSource) Cancel() {
+	s.mutex.Lock()
+	if s.canceled {
+		s.mutex.Unlock()
+		return
+	}
+	s.canceled = true
+	callbacks := s.callbacks
+	s.callbacks = nil
+	s.mutex.Unlock()
+
+	// cancel the context first so callbacks observe the canceled context
+	s.cancel()
+	for _, r := range callbacks {
+		r.callback()
+	}
+}
+
+func (s *cancellationSource) CancelAfter(duration time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.canceled {
+		return
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = s.clock.AfterFunc(duration, s.Cancel)
+}
+
+func (s *cancellationSource) IsCancellationRequested() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.canceled
+}
+
+func (s *cancellationSource) Context() context.Context {
+	return s.context
+}
+
+func (s *cancellationSource) Register(callback func()) io.Closer {
+	r := &registration{
+		source:   s,
+		callback: callback,
+	}
+	s.mutex.Lock()
+	if s.canceled {
+		s.mutex.Unlock()
+		callback()
+		return r
+	}
+	s.callbacks = append(s.callbacks, r)
+	s.mutex.Unlock()
+	return r
+}
+
+func (s *cancellationSource) unregister(r *registration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, item := range s.callbacks {
+		if item != r {
+			continue
+		}
+		s.callbacks = append(s.callbacks[:i], s.callbacks[i+1:]...)
+		return
+	}
+}
+
+func (s *cancellationSource) Close() error {
+	s.mutex.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	links := s.links
+	s.links = nil
+	s.mutex.Unlock()
+
+	for _, link := range links {
+		link.Close()
+	}
+	return nil
+}
+
+func (r *registration) Close() error {
+	r.source.unregister(r)
+	return nil
+}
+
+// contextWatchers shares a single routine between all callbacks registered on the same context
+var contextWatchers = struct {
+	sync.Mutex
+	watchers map[<-chan struct{}]*contextWatcher
+}{
+	watchers: map[<-chan struct{}]*contextWatcher{},
+}
+
+type contextWatcher struct {
+	done      <-chan struct{}
+	stop      chan struct{}
+	callbacks []*contextRegistration
+}
+
+type contextRegistration struct {
+	watcher  *contextWatcher
+	callback func()
+}
+
+type noopCloser struct{}
+
+func (noopCloser) Close() error {
+	return nil
+}
+
+// registerContext adds a callback that is called when the context is done. If the context is already
+// done the callback is called immediately, a context that is never done is not observed. All callbacks
+// of a context share one routine which exits when the context is done or the last registration is closed.
+func registerContext(ctx context.Context, callback func()) io.Closer {
+	done := ctx.Done()
+	if done == nil {
+		return noopCloser{}
+	}
+	select {
+	case <-done:
+		callback()
+		return noopCloser{}
+	default:
+	}
+
+	contextWatchers.Lock()
+	defer contextWatchers.Unlock()
+	w, ok := contextWatchers.watchers[done]
+	if !ok {
+		w = &contextWatcher{
+			done: done,
+			stop: make(chan struct{}),
+		}
+		contextWatchers.watchers[done] = w
+		go w.watch()
+	}
+	r := &contextRegistration{
+		watcher:  w,
+		callback: callback,
+	}
+	w.callbacks = append(w.callbacks, r)
+	return r
+}
+
+func (w *contextWatcher) watch() {
+	select {
+	case <-w.done:
+	case <-w.stop:
+		return
+	}
+	contextWatchers.Lock()
+	if contextWatchers.watchers[w.done] == w {
+		delete(contextWatchers.watchers, w.done)
+	}
+	callbacks := w.callbacks
+	w.callbacks = nil
+	contextWatchers.Unlock()
+
+	for _, r := range callbacks {
+		r.callback()
+	}
+}
+
+func (r *contextRegistration) Close() error {
+	contextWatchers.Lock()
+	defer contextWatchers.Unlock()
+	w := r.watcher
+	for i, item := range w.callbacks {
+		if item != r {
+			continue
+		}
+		w.callbacks = append(w.callbacks[:i], w.callbacks[i+1:]...)
+		break
+	}
+	// stop the routine of a context that is no longer observed
+	if len(w.callbacks) == 0 && contextWatchers.watchers[w.done] == w {
+		delete(contextWatchers.watchers, w.done)
+		close(w.stop)
+	}
+	return nil
+}
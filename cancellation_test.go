@@ -0,0 +1,326 @@
+package task_test
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+	"github.com/patrickhuber/go-task/tasktest"
+)
+
+var _ = Describe("CancellationSource", func() {
+	var (
+		source task.CancellationSource
+	)
+	BeforeEach(func() {
+		source = task.NewCancellationSource()
+	})
+	AfterEach(func() {
+		Expect(source.Close()).To(BeNil())
+	})
+	It("can cancel", func() {
+		Expect(source.IsCancellationRequested()).To(BeFalse())
+		source.Cancel()
+		Expect(source.IsCancellationRequested()).To(BeTrue())
+		Expect(source.Context().Err()).To(Equal(context.Canceled))
+	})
+	It("can cancel after", func() {
+		source.CancelAfter(time.Millisecond)
+		Eventually(source.IsCancellationRequested).Should(BeTrue())
+	})
+	It("calls registered callbacks", func() {
+		count := 0
+		source.Register(func() { count++ })
+		source.Cancel()
+		source.Cancel()
+		Expect(count).To(Equal(1))
+	})
+	It("calls callback immediately when canceled", func() {
+		count := 0
+		source.Cancel()
+		source.Register(func() { count++ })
+		Expect(count).To(Equal(1))
+	})
+	It("can unregister", func() {
+		count := 0
+		registration := source.Register(func() { count++ })
+		Expect(registration.Close()).To(BeNil())
+		source.Cancel()
+		Expect(count).To(Equal(0))
+	})
+	Describe("Linked", func() {
+		It("cancels when any source cancels", func() {
+			other := task.NewCancellationSource()
+			linked := task.NewLinkedCancellationSource(source, other)
+			defer linked.Close()
+			other.Cancel()
+			Expect(linked.IsCancellationRequested()).To(BeTrue())
+			Expect(source.IsCancellationRequested()).To(BeFalse())
+		})
+		It("does not cancel after close", func() {
+			linked := task.NewLinkedCancellationSource(source)
+			Expect(linked.Close()).To(BeNil())
+			source.Cancel()
+			Expect(linked.IsCancellationRequested()).To(BeFalse())
+		})
+	})
+	Describe("WithCancellation", func() {
+		It("cancels task", func() {
+			observer := NewTestObserver()
+			t := task.RunContextErrAction(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}, task.WithCancellation(source), task.WithScheduler(task.NewQueueScheduler()))
+			t.Subscribe(observer)
+			source.Cancel()
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(observer.canceledCount).To(Equal(1))
+			Expect(observer.completedCount).To(Equal(1))
+			Expect(t.Wait()).To(Equal(context.Canceled))
+		})
+		It("stops running delegate", func() {
+			started := make(chan struct{})
+			stopped := make(chan struct{})
+			task.RunContextErrAction(func(ctx context.Context) error {
+				defer close(stopped)
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			}, task.WithCancellation(source))
+			<-started
+			source.Cancel()
+			Eventually(stopped).Should(BeClosed())
+		})
+		It("cancels continuations", func() {
+			completion := task.NewCompletionSource()
+			count := 0
+			c := completion.Task().ContinueAction(func(t task.Task) {
+				count++
+			}, task.CancelOn(source))
+			source.Cancel()
+			Expect(c.Wait()).ToNot(BeNil())
+			Expect(c.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+		It("does not run canceled task", func() {
+			source.Cancel()
+			count := 0
+			t := task.RunAction(func() {
+				count++
+			}, task.WithCancellation(source))
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("Cancel", func() {
+	It("cancels task", func() {
+		scheduler := task.NewQueueScheduler()
+		t := task.RunAction(func() {}, task.WithScheduler(scheduler))
+		t.Cancel()
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(t.Wait()).To(Equal(context.Canceled))
+	})
+	It("cancels the delegate context", func() {
+		started := make(chan struct{})
+		t := task.RunContextErrAction(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		<-started
+		t.Cancel()
+		Expect(t.Wait()).To(Equal(context.Canceled))
+	})
+	It("does not change completed task", func() {
+		t := task.FromResult(1)
+		t.Cancel()
+		Expect(t.IsSuccess()).To(BeTrue())
+	})
+	It("leaves the transition of a running delegate to the delegate", func() {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		t := task.RunFunc(func() interface{} {
+			close(started)
+			<-release
+			return 1
+		})
+		var runs int32
+		continuation := t.ContinueAction(func(task.Task) {
+			atomic.AddInt32(&runs, 1)
+		})
+		<-started
+		t.Cancel()
+		Consistently(t.Status, "50ms").Should(Equal(task.StatusRunning))
+		Expect(atomic.LoadInt32(&runs)).To(Equal(int32(0)))
+		close(release)
+		Expect(continuation.Wait()).To(BeNil())
+		Expect(t.IsSuccess()).To(BeTrue())
+		Expect(t.Result()).To(Equal(1))
+	})
+})
+
+var _ = Describe("Context cancellation", func() {
+	var (
+		next, canceled, completed int32
+		observer                  task.Observer
+	)
+	BeforeEach(func() {
+		next, canceled, completed = 0, 0, 0
+		observer = task.NewObserver(func(interface{}) {
+			atomic.AddInt32(&next, 1)
+		}, func() {
+			atomic.AddInt32(&completed, 1)
+		}, func(error) {
+			atomic.AddInt32(&canceled, 1)
+		}, nil)
+	})
+	It("cancels queued task when started", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		scheduler := task.NewQueueScheduler()
+		ran := false
+		t := task.RunAction(func() {
+			ran = true
+		}, task.WithContext(ctx), task.WithScheduler(scheduler))
+		t.Subscribe(observer)
+		cancel()
+		t.Start()
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(ran).To(BeFalse())
+		Expect(atomic.LoadInt32(&canceled)).To(Equal(int32(1)))
+		Expect(atomic.LoadInt32(&completed)).To(Equal(int32(1)))
+	})
+	It("cancels task queued with a done context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		scheduler := task.NewQueueScheduler()
+		t := task.RunAction(func() {}, task.WithContext(ctx), task.WithScheduler(scheduler))
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(scheduler.Dequeue()).To(BeFalse())
+	})
+	It("leaves the transition of a running delegate to the delegate", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		release := make(chan struct{})
+		t := task.RunAction(func() {
+			close(started)
+			<-release
+		}, task.WithContext(ctx))
+		t.Subscribe(observer)
+		<-started
+		cancel()
+		Consistently(t.Status, "50ms").Should(Equal(task.StatusRunning))
+		close(release)
+		Eventually(t.Status).Should(Equal(task.StatusSuccess))
+		Expect(atomic.LoadInt32(&next)).To(Equal(int32(1)))
+		Expect(atomic.LoadInt32(&canceled)).To(Equal(int32(0)))
+		Expect(atomic.LoadInt32(&completed)).To(Equal(int32(1)))
+	})
+	It("returns the context error from Wait while the delegate runs", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		release := make(chan struct{})
+		t := task.RunAction(func() {
+			close(started)
+			<-release
+		}, task.WithContext(ctx))
+		t.Subscribe(observer)
+		<-started
+		cancel()
+		Expect(t.Wait()).To(Equal(context.Canceled))
+		Expect(t.IsCanceled()).To(BeTrue())
+		close(release)
+		Consistently(t.Status, "50ms").Should(Equal(task.StatusCanceled))
+		Expect(atomic.LoadInt32(&next)).To(Equal(int32(0)))
+		Expect(atomic.LoadInt32(&canceled)).To(Equal(int32(1)))
+		Expect(atomic.LoadInt32(&completed)).To(Equal(int32(1)))
+	})
+	It("cancels a running delegate when the timeout elapses", func() {
+		release := make(chan struct{})
+		defer close(release)
+		t := task.RunAction(func() {
+			<-release
+		}, task.WithTimeout(10*time.Millisecond))
+		t.Subscribe(observer)
+		Eventually(func() int32 { return atomic.LoadInt32(&completed) }).Should(Equal(int32(1)))
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(t.Error()).To(Equal(context.DeadlineExceeded))
+		Expect(atomic.LoadInt32(&canceled)).To(Equal(int32(1)))
+	})
+	It("shares one routine between the tasks of a context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		before := runtime.NumGoroutine()
+		sources := []task.CompletionSource{}
+		for i := 0; i < 100; i++ {
+			sources = append(sources, task.NewCompletionSource(task.WithContext(ctx)))
+		}
+		Expect(runtime.NumGoroutine() - before).To(BeNumerically("<", 10))
+		cancel()
+		for _, source := range sources {
+			Eventually(source.Task().IsCanceled).Should(BeTrue())
+		}
+	})
+	It("cancels without wait", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		delay := task.Delay(time.Hour, task.WithContext(ctx))
+		source := task.NewCompletionSource(task.WithContext(ctx))
+		queued := task.RunAction(func() {}, task.WithContext(ctx), task.WithScheduler(task.NewQueueScheduler()))
+		queued.Subscribe(observer)
+		cancel()
+		Eventually(delay.IsCanceled).Should(BeTrue())
+		Eventually(source.Task().IsCanceled).Should(BeTrue())
+		Eventually(func() int32 { return atomic.LoadInt32(&completed) }).Should(Equal(int32(1)))
+		Expect(queued.IsCanceled()).To(BeTrue())
+		Expect(queued.Error()).To(Equal(context.Canceled))
+		Expect(atomic.LoadInt32(&canceled)).To(Equal(int32(1)))
+	})
+	It("cancels running delegate that observes the context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		t := task.RunContextErrAction(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}, task.WithContext(ctx))
+		t.Subscribe(observer)
+		<-started
+		cancel()
+		Expect(t.Wait()).To(Equal(context.Canceled))
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(atomic.LoadInt32(&canceled)).To(Equal(int32(1)))
+		Expect(atomic.LoadInt32(&completed)).To(Equal(int32(1)))
+	})
+	It("cancels on virtual timeout deterministically", func() {
+		scheduler := tasktest.NewVirtualScheduler(time.Now())
+		ran := false
+		t := task.NewAction(func() {
+			ran = true
+		}, task.WithScheduler(scheduler), task.WithTimeout(time.Second))
+		scheduler.AdvanceBy(time.Second)
+		Expect(t.IsCanceled()).To(BeTrue())
+		t.Start()
+		Expect(t.Error()).To(Equal(context.DeadlineExceeded))
+		Expect(ran).To(BeFalse())
+	})
+	It("runs continuations once", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		var runs int32
+		t := task.RunContextErrAction(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, task.WithContext(ctx))
+		continuation := t.ContinueAction(func(task.Task) {
+			atomic.AddInt32(&runs, 1)
+		})
+		cancel()
+		Expect(continuation.Wait()).To(BeNil())
+		Consistently(func() int32 { return atomic.LoadInt32(&runs) }, "50ms").Should(Equal(int32(1)))
+	})
+})
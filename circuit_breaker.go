@@ -0,0 +1,276 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen faults tasks that run while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// errDelegatePanic records a panic of the wrapped delegate as a failure
+var errDelegatePanic = errors.New("circuit breaker delegate panicked")
+
+type CircuitState string
+
+const (
+	// CircuitClosed allows all calls and counts failures
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rejects all calls with ErrCircuitOpen until the cool down elapses
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen allows a single trial call, success closes the circuit and failure opens it again
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreaker stops calling a failing dependency. State transitions are published to
+// subscribed observers with OnNext and the new CircuitState.
+type CircuitBreaker interface {
+	Observable
+	// State returns the current state of the circuit
+	State() CircuitState
+	// Wrap returns a delegate that calls the given delegate through the circuit breaker
+	Wrap(ErrFuncWith) ErrFuncWith
+}
+
+type CircuitBreakerOption func(b *circuitBreaker)
+
+// WithConsecutiveFailures opens the circuit after the given number of consecutive failures. The default is 5, zero disables the threshold.
+func WithConsecutiveFailures(failures int) CircuitBreakerOption {
+	return func(b *circuitBreaker) {
+		b.consecutiveThreshold = failures
+	}
+}
+
+// WithFailureRatio opens the circuit when the ratio of failures in the last window calls reaches the ratio
+func WithFailureRatio(ratio float64, window int) CircuitBreakerOption {
+	return func(b *circuitBreaker) {
+		b.ratioThreshold = ratio
+		b.window = make([]bool, window)
+	}
+}
+
+// WithCoolDown sets how long the circuit stays open before a trial call is allowed. The default is 10 seconds.
+func WithCoolDown(coolDown time.Duration) CircuitBreakerOption {
+	return func(b *circuitBreaker) {
+		b.coolDown = coolDown
+	}
+}
+
+// WithCircuitBreakerClock sets the clock used to measure the cool down. The default is the DefaultClock.
+func WithCircuitBreakerClock(clock Clock) CircuitBreakerOption {
+	return func(b *circuitBreaker) {
+		b.clock = clock
+	}
+}
+
+// WithCircuitBreaker runs the task delegate through the circuit breaker. While the circuit is open
+// the delegate is not called and the task faults with ErrCircuitOpen.
+func WithCircuitBreaker(breaker CircuitBreaker) RunOption {
+	return func(t *task) {
+		b, ok := breaker.(*circuitBreaker)
+		if !ok || t.delegate == nil {
+			return
+		}
+		t.delegate = b.wrap(t.delegate)
+	}
+}
+
+type circuitBreaker struct {
+	mutex                sync.Mutex
+	state                CircuitState
+	openedAt             time.Time
+	trial                bool
+	generation           uint64
+	consecutive          int
+	consecutiveThreshold int
+	ratioThreshold       float64
+	window               []bool
+	windowIndex          int
+	windowCount          int
+	coolDown             time.Duration
+	clock                Clock
+	tracker              Tracker
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(options ...CircuitBreakerOption) CircuitBreaker {
+	b := &circuitBreaker{
+		state:                CircuitClosed,
+		consecutiveThreshold: 5,
+		coolDown:             10 * time.Second,
+		clock:                DefaultClock(),
+		tracker:              NewTracker(),
+	}
+	for _, opt := range options {
+		opt(b)
+	}
+	return b
+}
+
+func (b *circuitBreaker) State() CircuitState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) Subscribe(o Observer) io.Closer {
+	return b.tracker.Subscribe(o)
+}
+
+func (b *circuitBreaker) Unsubscribe(o Observer) {
+	b.tracker.Unsubscribe(o)
+}
+
+func (b *circuitBreaker) Wrap(errFuncWith ErrFuncWith) ErrFuncWith {
+	delegate := b.wrap(ignoreContext(errFuncWith))
+	return func(state interface{}) (interface{}, error) {
+		return delegate(context.Background(), state)
+	}
+}
+
+func (b *circuitBreaker) wrap(delegate ContextErrFuncWith) ContextErrFuncWith {
+	return func(ctx context.Context, state interface{}) (result interface{}, err error) {
+		call, ok := b.allow()
+		if !ok {
+			return nil, ErrCircuitOpen
+		}
+		// record in a defer so a panic in the delegate still ends the half-open trial
+		panicked := true
+		defer func() {
+			if panicked {
+				err = errDelegatePanic
+			}
+			b.record(call, err)
+		}()
+		result, err = delegate(ctx, state)
+		panicked = false
+		return result, err
+	}
+}
+
+// circuitCall identifies a call allowed by the circuit breaker
+type circuitCall struct {
+	// generation is the state generation the call started in
+	generation uint64
+	// trial is true for the single call allowed while half-open
+	trial bool
+}
+
+// allow returns the call and true if the call may proceed
+func (b *circuitBreaker) allow() (circuitCall, bool) {
+	b.mutex.Lock()
+	var transitions []CircuitState
+	defer func() {
+		b.mutex.Unlock()
+		b.publish(transitions)
+	}()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.clock.Now().Before(b.openedAt.Add(b.coolDown)) {
+			return circuitCall{}, false
+		}
+		transitions = append(transitions, b.transition(CircuitHalfOpen))
+		b.trial = true
+		return circuitCall{generation: b.generation, trial: true}, true
+	case CircuitHalfOpen:
+		if b.trial {
+			return circuitCall{}, false
+		}
+		b.trial = true
+		return circuitCall{generation: b.generation, trial: true}, true
+	default:
+		return circuitCall{generation: b.generation}, true
+	}
+}
+
+// record counts the outcome of a call. Cancellation is neither a failure nor a success, a canceled
+// trial leaves the circuit half-open so the next call is the trial. Calls that started before the
+// last transition are ignored, only the trial decides the half-open state.
+func (b *circuitBreaker) record(call circuitCall, err error) {
+	canceled := errors.Is(err, context.Canceled)
+	failure := err != nil && !canceled
+
+	b.mutex.Lock()
+	var transitions []CircuitState
+	defer func() {
+		b.mutex.Unlock()
+		b.publish(transitions)
+	}()
+
+	if call.generation != b.generation {
+		return
+	}
+	switch b.state {
+	case CircuitHalfOpen:
+		if !call.trial {
+			return
+		}
+		b.trial = false
+		switch {
+		case canceled:
+		case failure:
+			transitions = append(transitions, b.open())
+		default:
+			transitions = append(transitions, b.transition(CircuitClosed))
+		}
+	case CircuitClosed:
+		if b.count(failure) {
+			transitions = append(transitions, b.open())
+		}
+	}
+}
+
+// count updates the failure counters and returns true if a threshold is reached
+func (b *circuitBreaker) count(failure bool) bool {
+	if failure {
+		b.consecutive++
+	} else {
+		b.consecutive = 0
+	}
+	if b.consecutiveThreshold > 0 && b.consecutive >= b.consecutiveThreshold {
+		return true
+	}
+	if len(b.window) == 0 {
+		return false
+	}
+	b.window[b.windowIndex] = failure
+	b.windowIndex = (b.windowIndex + 1) % len(b.window)
+	if b.windowCount < len(b.window) {
+		b.windowCount++
+	}
+	if b.windowCount < len(b.window) {
+		return false
+	}
+	failures := 0
+	for _, f := range b.window {
+		if f {
+			failures++
+		}
+	}
+	return float64(failures)/float64(len(b.window)) >= b.ratioThreshold
+}
+
+func (b *circuitBreaker) open() CircuitState {
+	b.openedAt = b.clock.Now()
+	return b.transition(CircuitOpen)
+}
+
+// transition changes the state, starts a new generation of calls and resets the counters
+func (b *circuitBreaker) transition(state CircuitState) CircuitState {
+	b.state = state
+	b.generation++
+	b.consecutive = 0
+	b.windowIndex = 0
+	b.windowCount = 0
+	return state
+}
+
+func (b *circuitBreaker) publish(transitions []CircuitState) {
+	for _, state := range transitions {
+		b.tracker.NotifyNext(state)
+	}
+}
@@ -0,0 +1,182 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+	"github.com/patrickhuber/go-task/tasktest"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	var (
+		clock    tasktest.Clock
+		breaker  task.CircuitBreaker
+		states   []interface{}
+		failing  task.ErrFuncWith
+		succeeds task.ErrFuncWith
+	)
+	BeforeEach(func() {
+		clock = tasktest.NewClock(time.Now())
+		states = []interface{}{}
+		failing = func(interface{}) (interface{}, error) {
+			return nil, fmt.Errorf("error")
+		}
+		succeeds = func(interface{}) (interface{}, error) {
+			return 1, nil
+		}
+	})
+	subscribe := func() {
+		breaker.Subscribe(task.NewObserver(func(state interface{}) {
+			states = append(states, state)
+		}, nil, nil, nil))
+	}
+	Describe("consecutive failures", func() {
+		BeforeEach(func() {
+			breaker = task.NewCircuitBreaker(
+				task.WithConsecutiveFailures(2),
+				task.WithCoolDown(time.Second),
+				task.WithCircuitBreakerClock(clock))
+			subscribe()
+		})
+		It("opens after threshold", func() {
+			f := breaker.Wrap(failing)
+			f(nil)
+			Expect(breaker.State()).To(Equal(task.CircuitClosed))
+			f(nil)
+			Expect(breaker.State()).To(Equal(task.CircuitOpen))
+			_, err := f(nil)
+			Expect(err).To(Equal(task.ErrCircuitOpen))
+			Expect(states).To(Equal([]interface{}{task.CircuitOpen}))
+		})
+		It("resets on success", func() {
+			breaker.Wrap(failing)(nil)
+			breaker.Wrap(succeeds)(nil)
+			breaker.Wrap(failing)(nil)
+			Expect(breaker.State()).To(Equal(task.CircuitClosed))
+		})
+		It("closes after successful trial", func() {
+			f := breaker.Wrap(failing)
+			f(nil)
+			f(nil)
+			clock.AdvanceBy(time.Second)
+			result, err := breaker.Wrap(succeeds)(nil)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(1))
+			Expect(breaker.State()).To(Equal(task.CircuitClosed))
+			Expect(states).To(Equal([]interface{}{task.CircuitOpen, task.CircuitHalfOpen, task.CircuitClosed}))
+		})
+		It("opens after failed trial", func() {
+			f := breaker.Wrap(failing)
+			f(nil)
+			f(nil)
+			clock.AdvanceBy(time.Second)
+			_, err := f(nil)
+			Expect(err).ToNot(Equal(task.ErrCircuitOpen))
+			Expect(breaker.State()).To(Equal(task.CircuitOpen))
+			_, err = f(nil)
+			Expect(err).To(Equal(task.ErrCircuitOpen))
+		})
+		It("stays half-open after canceled trial", func() {
+			f := breaker.Wrap(failing)
+			f(nil)
+			f(nil)
+			clock.AdvanceBy(time.Second)
+			_, err := breaker.Wrap(func(interface{}) (interface{}, error) {
+				return nil, context.Canceled
+			})(nil)
+			Expect(err).To(Equal(context.Canceled))
+			Expect(breaker.State()).To(Equal(task.CircuitHalfOpen))
+			_, err = breaker.Wrap(succeeds)(nil)
+			Expect(err).To(BeNil())
+			Expect(breaker.State()).To(Equal(task.CircuitClosed))
+		})
+		It("opens after panicked trial", func() {
+			f := breaker.Wrap(failing)
+			f(nil)
+			f(nil)
+			clock.AdvanceBy(time.Second)
+			Expect(func() {
+				breaker.Wrap(func(interface{}) (interface{}, error) {
+					panic("trial")
+				})(nil)
+			}).To(PanicWith("trial"))
+			Expect(breaker.State()).To(Equal(task.CircuitOpen))
+			clock.AdvanceBy(time.Second)
+			_, err := breaker.Wrap(succeeds)(nil)
+			Expect(err).To(BeNil())
+			Expect(breaker.State()).To(Equal(task.CircuitClosed))
+		})
+		It("ignores calls that started before the circuit opened", func() {
+			blocking := func(started, release chan struct{}) chan struct{} {
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					breaker.Wrap(func(interface{}) (interface{}, error) {
+						close(started)
+						<-release
+						return 1, nil
+					})(nil)
+				}()
+				<-started
+				return done
+			}
+			oldRelease := make(chan struct{})
+			old := blocking(make(chan struct{}), oldRelease)
+			f := breaker.Wrap(failing)
+			f(nil)
+			f(nil)
+			clock.AdvanceBy(time.Second)
+			trialRelease := make(chan struct{})
+			trial := blocking(make(chan struct{}), trialRelease)
+			Expect(breaker.State()).To(Equal(task.CircuitHalfOpen))
+			close(oldRelease)
+			<-old
+			Expect(breaker.State()).To(Equal(task.CircuitHalfOpen))
+			close(trialRelease)
+			<-trial
+			Expect(breaker.State()).To(Equal(task.CircuitClosed))
+			Expect(states).To(Equal([]interface{}{task.CircuitOpen, task.CircuitHalfOpen, task.CircuitClosed}))
+		})
+	})
+	Describe("failure ratio", func() {
+		It("opens when ratio reached", func() {
+			breaker = task.NewCircuitBreaker(
+				task.WithConsecutiveFailures(0),
+				task.WithFailureRatio(0.5, 4),
+				task.WithCircuitBreakerClock(clock))
+			breaker.Wrap(failing)(nil)
+			breaker.Wrap(succeeds)(nil)
+			breaker.Wrap(failing)(nil)
+			Expect(breaker.State()).To(Equal(task.CircuitClosed))
+			breaker.Wrap(succeeds)(nil)
+			Expect(breaker.State()).To(Equal(task.CircuitOpen))
+		})
+	})
+	Describe("WithCircuitBreaker", func() {
+		It("faults tasks while open", func() {
+			breaker = task.NewCircuitBreaker(task.WithConsecutiveFailures(1), task.WithCircuitBreakerClock(clock))
+			count := 0
+			run := func() task.Task {
+				return task.RunErrFuncWith(func(interface{}) (interface{}, error) {
+					count++
+					return nil, fmt.Errorf("error")
+				}, task.WithCircuitBreaker(breaker))
+			}
+			Expect(run().Wait()).ToNot(BeNil())
+			Expect(run().Wait()).To(Equal(task.ErrCircuitOpen))
+			Expect(count).To(Equal(1))
+		})
+		It("records recovered panics as failures", func() {
+			breaker = task.NewCircuitBreaker(task.WithConsecutiveFailures(1), task.WithCircuitBreakerClock(clock))
+			t := task.RunAction(func() {
+				panic("error")
+			}, task.WithCircuitBreaker(breaker))
+			Expect(t.Wait()).To(BeAssignableToTypeOf(task.NewPanicError(nil, nil, nil)))
+			Expect(breaker.State()).To(Equal(task.CircuitOpen))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package task
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the current time and timers. Delay, WithTimeout and CancelAfter consult the clock
+// so tests can replace it with a virtual clock, see the tasktest package.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// AfterFunc calls f after the duration elapses
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a timer created by a Clock
+type Timer interface {
+	// Stop prevents the timer from firing. It returns false if the timer already fired or was stopped.
+	Stop() bool
+}
+
+type realClock struct {
+}
+
+var defaultClock Clock = &realClock{}
+
+// DefaultClock returns the clock backed by the time package
+func DefaultClock() Clock {
+	return defaultClock
+}
+
+func (c *realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c *realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// clockProvider is implemented by schedulers that supply the clock for the tasks they run
+type clockProvider interface {
+	Clock() Clock
+}
+
+// clockContext is a context that expires with a timer of a Clock
+type clockContext struct {
+	context.Context
+	deadline time.Time
+	expired  int32
+}
+
+// withClockTimeout returns a context that is canceled with context.DeadlineExceeded when the clock reaches the timeout
+func withClockTimeout(parent context.Context, clock Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if clock == defaultClock {
+		return context.WithTimeout(parent, timeout)
+	}
+	ctx, cancel := context.WithCancel(parent)
+	c := &clockContext{
+		Context:  ctx,
+		deadline: clock.Now().Add(timeout),
+	}
+	timer := clock.AfterFunc(timeout, func() {
+		if ctx.Err() == nil {
+			atomic.StoreInt32(&c.expired, 1)
+		}
+		cancel()
+	})
+	return c, func() {
+		timer.Stop()
+		cancel()
+	}
+}
+
+func (c *clockContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *clockContext) Err() error {
+	if atomic.LoadInt32(&c.expired) == 1 {
+		return context.DeadlineExceeded
+	}
+	return c.Context.Err()
+}
@@ -0,0 +1,78 @@
+package task
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTaskCompleted is returned when attempting to complete a task that is already complete
+var ErrTaskCompleted = errors.New("task is already completed")
+
+// CompletionSource creates a task whose outcome is set by external code
+type CompletionSource interface {
+	// Task returns the task controlled by the completion source
+	Task() Task
+	// SetResult transitions the task to StatusSuccess with the given result
+	SetResult(result interface{}) error
+	// SetError transitions the task to StatusFaulted with the given error
+	SetError(err error) error
+	// SetCanceled transitions the task to StatusCanceled
+	SetCanceled() error
+	// TrySetResult attempts to transition the task to StatusSuccess, it returns false if the task is already complete
+	TrySetResult(result interface{}) bool
+	// TrySetError attempts to transition the task to StatusFaulted, it returns false if the task is already complete
+	TrySetError(err error) bool
+	// TrySetCanceled attempts to transition the task to StatusCanceled, it returns false if the task is already complete
+	TrySetCanceled() bool
+}
+
+type completionSource struct {
+	task *task
+}
+
+// NewCompletionSource creates a completion source with an unstarted task. The task is never
+// queued on a scheduler, the scheduler option only applies to continuations of the task.
+func NewCompletionSource(options ...RunOption) CompletionSource {
+	t := new(nil)
+	t.apply(options...)
+	return &completionSource{
+		task: t,
+	}
+}
+
+func (s *completionSource) Task() Task {
+	return s.task
+}
+
+func (s *completionSource) SetResult(result interface{}) error {
+	if !s.TrySetResult(result) {
+		return ErrTaskCompleted
+	}
+	return nil
+}
+
+func (s *completionSource) SetError(err error) error {
+	if !s.TrySetError(err) {
+		return ErrTaskCompleted
+	}
+	return nil
+}
+
+func (s *completionSource) SetCanceled() error {
+	if !s.TrySetCanceled() {
+		return ErrTaskCompleted
+	}
+	return nil
+}
+
+func (s *completionSource) TrySetResult(result interface{}) bool {
+	return s.task.setSuccess(result)
+}
+
+func (s *completionSource) TrySetError(err error) bool {
+	return s.task.setFaulted(err)
+}
+
+func (s *completionSource) TrySetCanceled() bool {
+	return s.task.setCanceled(context.Canceled)
+}
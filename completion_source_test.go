@@ -0,0 +1,81 @@
+package task_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("CompletionSource", func() {
+	var (
+		source task.CompletionSource
+	)
+	BeforeEach(func() {
+		source = task.NewCompletionSource()
+	})
+	It("is created", func() {
+		Expect(source.Task().Status()).To(Equal(task.StatusCreated))
+		Expect(source.Task().IsCompleted()).To(BeFalse())
+	})
+	It("can set result", func() {
+		Expect(source.SetResult(1)).To(BeNil())
+		Expect(source.Task().Wait()).To(BeNil())
+		Expect(source.Task().IsSuccess()).To(BeTrue())
+		Expect(source.Task().Result()).To(Equal(1))
+	})
+	It("can set error", func() {
+		Expect(source.SetError(fmt.Errorf("error"))).To(BeNil())
+		Expect(source.Task().Wait()).ToNot(BeNil())
+		Expect(source.Task().IsFaulted()).To(BeTrue())
+	})
+	It("can set canceled", func() {
+		Expect(source.SetCanceled()).To(BeNil())
+		Expect(source.Task().Wait()).ToNot(BeNil())
+		Expect(source.Task().IsCanceled()).To(BeTrue())
+	})
+	It("returns error when already completed", func() {
+		Expect(source.SetResult(1)).To(BeNil())
+		Expect(source.SetResult(2)).To(Equal(task.ErrTaskCompleted))
+		Expect(source.SetError(fmt.Errorf("error"))).To(Equal(task.ErrTaskCompleted))
+		Expect(source.SetCanceled()).To(Equal(task.ErrTaskCompleted))
+		Expect(source.Task().Result()).To(Equal(1))
+	})
+	It("can try set", func() {
+		Expect(source.TrySetError(fmt.Errorf("error"))).To(BeTrue())
+		Expect(source.TrySetResult(1)).To(BeFalse())
+		Expect(source.TrySetCanceled()).To(BeFalse())
+		Expect(source.Task().IsFaulted()).To(BeTrue())
+	})
+	It("unblocks wait", func() {
+		go func() {
+			source.SetResult(1)
+		}()
+		Expect(source.Task().Wait()).To(BeNil())
+		Expect(source.Task().Result()).To(Equal(1))
+	})
+	It("notifies observers", func() {
+		observer := NewTestObserver()
+		source.Task().Subscribe(observer)
+		source.SetResult(1)
+		Expect(observer.nextCount).To(Equal(1))
+		Expect(observer.completedCount).To(Equal(1))
+	})
+	It("runs continuations", func() {
+		c := source.Task().ContinueFunc(func(t task.Task) interface{} {
+			return t.Result().(int) + 1
+		})
+		source.SetResult(1)
+		Expect(c.Wait()).To(BeNil())
+		Expect(c.Result()).To(Equal(2))
+	})
+	It("works with WhenAll", func() {
+		other := task.NewCompletionSource()
+		t := task.WhenAll(source.Task(), other.Task())
+		source.SetResult(1)
+		Expect(t.IsCompleted()).To(BeFalse())
+		other.SetError(fmt.Errorf("error"))
+		Expect(t.Wait()).ToNot(BeNil())
+	})
+})
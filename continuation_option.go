@@ -0,0 +1,119 @@
+package task
+
+// ContinuationOption configures when and where a continuation runs
+type ContinuationOption func(o *continuationOptions)
+
+type continuationOptions struct {
+	notOnSuccess         bool
+	notOnFaulted         bool
+	notOnCanceled        bool
+	executeSynchronously bool
+	lazyCancellation     bool
+	inheritPriority      bool
+	scheduler            Scheduler
+	cancellation         CancellationSource
+}
+
+func newContinuationOptions(options ...ContinuationOption) *continuationOptions {
+	o := &continuationOptions{}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+// runsOn returns true if the continuation should run when the antecedent completes with the given status
+func (o *continuationOptions) runsOn(status TaskStatus) bool {
+	switch status {
+	case StatusSuccess:
+		return !o.notOnSuccess
+	case StatusFaulted:
+		return !o.notOnFaulted
+	case StatusCanceled:
+		return !o.notOnCanceled
+	default:
+		return true
+	}
+}
+
+// OnlyOnRanToCompletion runs the continuation only if the antecedent completed successfully
+func OnlyOnRanToCompletion() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.notOnFaulted = true
+		o.notOnCanceled = true
+	}
+}
+
+// OnlyOnFaulted runs the continuation only if the antecedent faulted
+func OnlyOnFaulted() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.notOnSuccess = true
+		o.notOnCanceled = true
+	}
+}
+
+// OnlyOnCanceled runs the continuation only if the antecedent was canceled
+func OnlyOnCanceled() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.notOnSuccess = true
+		o.notOnFaulted = true
+	}
+}
+
+// NotOnRanToCompletion skips the continuation if the antecedent completed successfully
+func NotOnRanToCompletion() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.notOnSuccess = true
+	}
+}
+
+// NotOnFaulted skips the continuation if the antecedent faulted
+func NotOnFaulted() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.notOnFaulted = true
+	}
+}
+
+// NotOnCanceled skips the continuation if the antecedent was canceled
+func NotOnCanceled() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.notOnCanceled = true
+	}
+}
+
+// ExecuteSynchronously runs the continuation on the routine that completes the antecedent
+// instead of queuing it on the scheduler. Use it for short continuations only.
+func ExecuteSynchronously() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.executeSynchronously = true
+	}
+}
+
+// RunOnScheduler queues the continuation on the given scheduler instead of the antecedent scheduler
+func RunOnScheduler(s Scheduler) ContinuationOption {
+	return func(o *continuationOptions) {
+		o.scheduler = s
+	}
+}
+
+// LazyCancellation prevents the continuation from transitioning to canceled before the antecedent completes
+func LazyCancellation() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.lazyCancellation = true
+	}
+}
+
+// CancelOn cancels the continuation when the source is canceled. Continuations do not observe the
+// cancellation of the antecedent, CancelOn is the only way to cancel a continuation before it runs.
+func CancelOn(source CancellationSource) ContinuationOption {
+	return func(o *continuationOptions) {
+		o.cancellation = source
+	}
+}
+
+// InheritPriority gives the continuation the priority of the antecedent
+func InheritPriority() ContinuationOption {
+	return func(o *continuationOptions) {
+		o.inheritPriority = true
+	}
+}
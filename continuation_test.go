@@ -1,6 +1,7 @@
 package task_test
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -49,4 +50,178 @@ var _ = Describe("Continuation", func() {
 			Expect(count).To(Equal(1))
 		})
 	})
+	It("can observe context", func() {
+		cancellation := task.NewCancellationSource()
+		source := task.NewCompletionSource()
+		started := make(chan struct{})
+		c := source.Task().ContinueContextErrAction(func(ctx context.Context, t task.Task) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}, task.CancelOn(cancellation))
+		source.SetResult(1)
+		<-started
+		cancellation.Cancel()
+		Expect(c.Wait()).To(Equal(context.Canceled))
+		Expect(c.IsCanceled()).To(BeTrue())
+	})
+	It("does not inherit the antecedent context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		antecedent := task.RunAction(func() {
+			<-release
+		}, task.WithContext(ctx))
+		c := antecedent.ContinueContextErrFuncWith(func(ctx context.Context, t task.Task, state interface{}) (interface{}, error) {
+			return ctx.Err(), nil
+		})
+		cancel()
+		close(release)
+		Expect(c.Wait()).To(BeNil())
+		Expect(c.IsSuccess()).To(BeTrue())
+		Expect(c.Result()).To(BeNil())
+	})
+	It("can pass state with context", func() {
+		c := task.FromResult(1).ContinueContextErrFuncWith(func(ctx context.Context, t task.Task, state interface{}) (interface{}, error) {
+			return t.Result().(int) + 1, nil
+		})
+		Expect(c.Wait()).To(BeNil())
+		Expect(c.Result()).To(Equal(2))
+	})
+	It("can chain continuations", func() {
+		source := task.NewCompletionSource()
+		c := source.Task().ContinueFunc(func(t task.Task) interface{} {
+			return t.Result().(int) + 1
+		}).ContinueFunc(func(t task.Task) interface{} {
+			return t.Result().(int) + 1
+		})
+		source.SetResult(0)
+		Expect(c.Wait()).To(BeNil())
+		Expect(c.Result()).To(Equal(2))
+	})
+	Describe("ContinuationOption", func() {
+		var (
+			count int
+		)
+		BeforeEach(func() {
+			count = 0
+		})
+		action := func(t task.Task) {
+			count++
+		}
+		It("runs only on ran to completion", func() {
+			c := task.FromResult(1).ContinueAction(action, task.OnlyOnRanToCompletion())
+			Expect(c.Wait()).To(BeNil())
+			Expect(count).To(Equal(1))
+		})
+		It("skips only on ran to completion when faulted", func() {
+			c := task.FromError(fmt.Errorf("error")).ContinueAction(action, task.OnlyOnRanToCompletion())
+			Expect(c.Wait()).ToNot(BeNil())
+			Expect(c.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+		It("runs only on faulted", func() {
+			c := task.FromError(fmt.Errorf("error")).ContinueAction(action, task.OnlyOnFaulted())
+			Expect(c.Wait()).To(BeNil())
+			Expect(count).To(Equal(1))
+		})
+		It("skips only on faulted when success", func() {
+			c := task.FromResult(1).ContinueAction(action, task.OnlyOnFaulted())
+			Expect(c.Wait()).ToNot(BeNil())
+			Expect(c.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+		It("runs only on canceled", func() {
+			source := task.NewCompletionSource()
+			c := source.Task().ContinueAction(action, task.OnlyOnCanceled())
+			source.SetCanceled()
+			Expect(c.Wait()).To(BeNil())
+			Expect(count).To(Equal(1))
+		})
+		It("skips not on canceled", func() {
+			source := task.NewCompletionSource()
+			c := source.Task().ContinueAction(action, task.NotOnCanceled())
+			source.SetCanceled()
+			Expect(c.Wait()).ToNot(BeNil())
+			Expect(c.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+		It("skips not on faulted", func() {
+			c := task.FromError(fmt.Errorf("error")).ContinueAction(action, task.NotOnFaulted())
+			Expect(c.Wait()).ToNot(BeNil())
+			Expect(count).To(Equal(0))
+		})
+		It("skips not on ran to completion", func() {
+			c := task.Completed().ContinueAction(action, task.NotOnRanToCompletion())
+			Expect(c.Wait()).ToNot(BeNil())
+			Expect(count).To(Equal(0))
+		})
+		It("executes synchronously", func() {
+			source := task.NewCompletionSource()
+			c := source.Task().ContinueAction(action, task.ExecuteSynchronously())
+			source.SetResult(1)
+			Expect(c.IsCompleted()).To(BeTrue())
+			Expect(count).To(Equal(1))
+		})
+		It("runs on scheduler", func() {
+			scheduler := task.NewQueueScheduler()
+			c := task.Completed().ContinueAction(action, task.RunOnScheduler(scheduler))
+			Expect(c.IsCompleted()).To(BeFalse())
+			Expect(scheduler.Dequeue()).To(BeTrue())
+			Expect(c.Wait()).To(BeNil())
+			Expect(count).To(Equal(1))
+		})
+		It("cancels eagerly", func() {
+			cancellation := task.NewCancellationSource()
+			source := task.NewCompletionSource()
+			c := source.Task().ContinueAction(action, task.CancelOn(cancellation))
+			cancellation.Cancel()
+			Expect(c.IsCanceled()).To(BeTrue())
+			source.SetResult(1)
+			Expect(count).To(Equal(0))
+		})
+		It("cancels lazily", func() {
+			cancellation := task.NewCancellationSource()
+			source := task.NewCompletionSource()
+			c := source.Task().ContinueAction(action, task.CancelOn(cancellation), task.LazyCancellation())
+			cancellation.Cancel()
+			Expect(c.IsCompleted()).To(BeFalse())
+			source.SetResult(1)
+			Expect(c.Wait()).ToNot(BeNil())
+			Expect(c.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+		It("runs when antecedent is canceled by its source", func() {
+			cancellation := task.NewCancellationSource()
+			antecedent := task.RunContextErrAction(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}, task.WithCancellation(cancellation))
+			c := antecedent.ContinueAction(action, task.OnlyOnCanceled())
+			cancellation.Cancel()
+			Expect(c.Wait()).To(BeNil())
+			Expect(antecedent.IsCanceled()).To(BeTrue())
+			Expect(c.IsSuccess()).To(BeTrue())
+			Expect(count).To(Equal(1))
+		})
+		It("runs when antecedent is canceled by its context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			antecedent := task.RunContextErrAction(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}, task.WithContext(ctx))
+			c := antecedent.ContinueAction(action, task.NotOnRanToCompletion(), task.NotOnFaulted())
+			cancel()
+			Expect(c.Wait()).To(BeNil())
+			Expect(count).To(Equal(1))
+		})
+		It("skips when antecedent is canceled by its source", func() {
+			cancellation := task.NewCancellationSource()
+			source := task.NewCompletionSource(task.WithCancellation(cancellation))
+			c := source.Task().ContinueAction(action, task.NotOnCanceled())
+			cancellation.Cancel()
+			Expect(c.Wait()).ToNot(BeNil())
+			Expect(c.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+	})
 })
@@ -2,9 +2,17 @@ package task
 
 import "time"
 
-// Delay calls time.Sleep with the given duration
+// Delay returns a task that completes after the given duration. The duration is measured with the
+// clock of the task, see WithClock. The task does not occupy the scheduler while it waits.
 func Delay(duration time.Duration, options ...RunOption) Task {
-	return RunAction(func() {
-		time.Sleep(duration)
-	}, options...)
+	t := new(nil)
+	t.apply(options...)
+	t.clock.AfterFunc(duration, func() {
+		if err := t.context.Err(); err != nil {
+			t.setCanceled(err)
+			return
+		}
+		t.setSuccess(nil)
+	})
+	return t
 }
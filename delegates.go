@@ -1,5 +1,7 @@
 package task
 
+import "context"
+
 type Action func()
 type ActionWith func(interface{})
 type ErrAction func() error
@@ -8,3 +10,5 @@ type Func func() interface{}
 type FuncWith func(interface{}) interface{}
 type ErrFunc func() (interface{}, error)
 type ErrFuncWith func(interface{}) (interface{}, error)
+type ContextErrAction func(context.Context) error
+type ContextErrFuncWith func(context.Context, interface{}) (interface{}, error)
@@ -0,0 +1,195 @@
+package task
+
+// Factory creates tasks with a set of default options. Options passed to a factory method are applied
+// after the defaults and override them.
+type Factory interface {
+	RunAction(Action, ...RunOption) Task
+	RunActionWith(ActionWith, ...RunOption) Task
+	RunErrAction(ErrAction, ...RunOption) Task
+	RunErrActionWith(ErrActionWith, ...RunOption) Task
+	RunFunc(Func, ...RunOption) Task
+	RunFuncWith(FuncWith, ...RunOption) Task
+	RunErrFunc(ErrFunc, ...RunOption) Task
+	RunErrFuncWith(ErrFuncWith, ...RunOption) Task
+	RunContextErrAction(ContextErrAction, ...RunOption) Task
+	RunContextErrFuncWith(ContextErrFuncWith, ...RunOption) Task
+
+	NewAction(Action, ...RunOption) Task
+	NewActionWith(ActionWith, ...RunOption) Task
+	NewErrAction(ErrAction, ...RunOption) Task
+	NewErrActionWith(ErrActionWith, ...RunOption) Task
+	NewFunc(Func, ...RunOption) Task
+	NewFuncWith(FuncWith, ...RunOption) Task
+	NewErrFunc(ErrFunc, ...RunOption) Task
+	NewErrFuncWith(ErrFuncWith, ...RunOption) Task
+	NewContextErrAction(ContextErrAction, ...RunOption) Task
+	NewContextErrFuncWith(ContextErrFuncWith, ...RunOption) Task
+
+	// ContinueWhenAll runs the continuation when all tasks complete. The continuation receives the
+	// task created by WhenAll. The continuation runs on the scheduler and is canceled by the
+	// cancellation source of the default run options.
+	ContinueWhenAll(tasks []Task, continuation ContinueErrFunc, options ...ContinuationOption) Task
+	// ContinueWhenAny runs the continuation when any task completes. The continuation receives the
+	// task created by WhenAny. The scheduler and cancellation source of the default run options are
+	// applied like ContinueWhenAll.
+	ContinueWhenAny(tasks []Task, continuation ContinueErrFunc, options ...ContinuationOption) Task
+	// Invoke runs each action as a task and returns a task created by WhenAll
+	Invoke(actions ...Action) Task
+}
+
+type FactoryOption func(f *factory)
+
+// WithDefaultRunOptions sets the options applied to every task created by the factory
+func WithDefaultRunOptions(options ...RunOption) FactoryOption {
+	return func(f *factory) {
+		f.runOptions = append(f.runOptions, options...)
+	}
+}
+
+// WithDefaultContinuationOptions sets the options applied to every continuation created by the factory
+func WithDefaultContinuationOptions(options ...ContinuationOption) FactoryOption {
+	return func(f *factory) {
+		f.continuationOptions = append(f.continuationOptions, options...)
+	}
+}
+
+type factory struct {
+	runOptions          []RunOption
+	continuationOptions []ContinuationOption
+}
+
+// NewFactory creates a factory with the given default options
+func NewFactory(options ...FactoryOption) Factory {
+	f := &factory{}
+	for _, opt := range options {
+		opt(f)
+	}
+	return f
+}
+
+// ParallelInvoke runs each action as a task and returns a task that completes when all actions complete
+func ParallelInvoke(actions ...Action) Task {
+	return NewFactory().Invoke(actions...)
+}
+
+// run returns the default run options followed by the given options
+func (f *factory) run(options []RunOption) []RunOption {
+	merged := make([]RunOption, 0, len(f.runOptions)+len(options))
+	merged = append(merged, f.runOptions...)
+	return append(merged, options...)
+}
+
+// continuation returns the scheduler and cancellation of the default run options, the default
+// continuation options and the given options in that order
+func (f *factory) continuation(options []ContinuationOption) []ContinuationOption {
+	// apply the default run options to an empty task to find the scheduler and cancellation
+	defaults := &task{}
+	for _, opt := range f.runOptions {
+		opt(defaults)
+	}
+	merged := make([]ContinuationOption, 0, len(f.continuationOptions)+len(options)+2)
+	if defaults.scheduler != nil {
+		merged = append(merged, RunOnScheduler(defaults.scheduler))
+	}
+	if defaults.cancellation != nil {
+		merged = append(merged, CancelOn(defaults.cancellation))
+	}
+	merged = append(merged, f.continuationOptions...)
+	return append(merged, options...)
+}
+
+func (f *factory) RunAction(action Action, options ...RunOption) Task {
+	return RunAction(action, f.run(options)...)
+}
+
+func (f *factory) RunActionWith(actionWith ActionWith, options ...RunOption) Task {
+	return RunActionWith(actionWith, f.run(options)...)
+}
+
+func (f *factory) RunErrAction(errAction ErrAction, options ...RunOption) Task {
+	return RunErrAction(errAction, f.run(options)...)
+}
+
+func (f *factory) RunErrActionWith(errActionWith ErrActionWith, options ...RunOption) Task {
+	return RunErrActionWith(errActionWith, f.run(options)...)
+}
+
+func (f *factory) RunFunc(fn Func, options ...RunOption) Task {
+	return RunFunc(fn, f.run(options)...)
+}
+
+func (f *factory) RunFuncWith(funcWith FuncWith, options ...RunOption) Task {
+	return RunFuncWith(funcWith, f.run(options)...)
+}
+
+func (f *factory) RunErrFunc(fn ErrFunc, options ...RunOption) Task {
+	return RunErrFunc(fn, f.run(options)...)
+}
+
+func (f *factory) RunErrFuncWith(errFuncWith ErrFuncWith, options ...RunOption) Task {
+	return RunErrFuncWith(errFuncWith, f.run(options)...)
+}
+
+func (f *factory) RunContextErrAction(contextErrAction ContextErrAction, options ...RunOption) Task {
+	return RunContextErrAction(contextErrAction, f.run(options)...)
+}
+
+func (f *factory) RunContextErrFuncWith(contextErrFuncWith ContextErrFuncWith, options ...RunOption) Task {
+	return RunContextErrFuncWith(contextErrFuncWith, f.run(options)...)
+}
+
+func (f *factory) NewAction(action Action, options ...RunOption) Task {
+	return NewAction(action, f.run(options)...)
+}
+
+func (f *factory) NewActionWith(actionWith ActionWith, options ...RunOption) Task {
+	return NewActionWith(actionWith, f.run(options)...)
+}
+
+func (f *factory) NewErrAction(errAction ErrAction, options ...RunOption) Task {
+	return NewErrAction(errAction, f.run(options)...)
+}
+
+func (f *factory) NewErrActionWith(errActionWith ErrActionWith, options ...RunOption) Task {
+	return NewErrActionWith(errActionWith, f.run(options)...)
+}
+
+func (f *factory) NewFunc(fn Func, options ...RunOption) Task {
+	return NewFunc(fn, f.run(options)...)
+}
+
+func (f *factory) NewFuncWith(funcWith FuncWith, options ...RunOption) Task {
+	return NewFuncWith(funcWith, f.run(options)...)
+}
+
+func (f *factory) NewErrFunc(fn ErrFunc, options ...RunOption) Task {
+	return NewErrFunc(fn, f.run(options)...)
+}
+
+func (f *factory) NewErrFuncWith(errFuncWith ErrFuncWith, options ...RunOption) Task {
+	return NewErrFuncWith(errFuncWith, f.run(options)...)
+}
+
+func (f *factory) NewContextErrAction(contextErrAction ContextErrAction, options ...RunOption) Task {
+	return NewContextErrAction(contextErrAction, f.run(options)...)
+}
+
+func (f *factory) NewContextErrFuncWith(contextErrFuncWith ContextErrFuncWith, options ...RunOption) Task {
+	return NewContextErrFuncWith(contextErrFuncWith, f.run(options)...)
+}
+
+func (f *factory) ContinueWhenAll(tasks []Task, continuation ContinueErrFunc, options ...ContinuationOption) Task {
+	return WhenAll(tasks...).ContinueErrFunc(continuation, f.continuation(options)...)
+}
+
+func (f *factory) ContinueWhenAny(tasks []Task, continuation ContinueErrFunc, options ...ContinuationOption) Task {
+	return WhenAny(tasks...).ContinueErrFunc(continuation, f.continuation(options)...)
+}
+
+func (f *factory) Invoke(actions ...Action) Task {
+	tasks := make([]Task, 0, len(actions))
+	for _, action := range actions {
+		tasks = append(tasks, f.RunAction(action))
+	}
+	return WhenAll(tasks...)
+}
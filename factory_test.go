@@ -0,0 +1,143 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("Factory", func() {
+	It("applies default run options", func() {
+		factory := task.NewFactory(task.WithDefaultRunOptions(task.WithState(1), task.WithPriority(5)))
+		t := factory.RunFuncWith(func(state interface{}) interface{} {
+			return state
+		})
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(1))
+		Expect(t.Priority()).To(Equal(5))
+	})
+	It("overrides default run options", func() {
+		factory := task.NewFactory(task.WithDefaultRunOptions(task.WithState(1)))
+		t := factory.RunFuncWith(func(state interface{}) interface{} {
+			return state
+		}, task.WithState(2))
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(2))
+	})
+	It("creates unstarted tasks", func() {
+		factory := task.NewFactory(task.WithDefaultRunOptions(task.WithState(1)))
+		t := factory.NewErrFuncWith(func(state interface{}) (interface{}, error) {
+			return state, nil
+		})
+		Expect(t.Status()).To(Equal(task.StatusCreated))
+		t.Start()
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(1))
+	})
+	It("cancels tasks with default context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		factory := task.NewFactory(task.WithDefaultRunOptions(task.WithContext(ctx)))
+		cancel()
+		t := factory.RunContextErrAction(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		t.Wait()
+		Expect(t.IsCanceled()).To(BeTrue())
+	})
+	Describe("ContinueWhenAll", func() {
+		It("continues after all tasks", func() {
+			factory := task.NewFactory()
+			tasks := []task.Task{
+				factory.RunFunc(func() interface{} { return 1 }),
+				factory.RunFunc(func() interface{} { return 2 }),
+			}
+			t := factory.ContinueWhenAll(tasks, func(when task.Task) (interface{}, error) {
+				results := when.Result().([]interface{})
+				return results[0].(int) + results[1].(int), nil
+			})
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(3))
+		})
+		It("applies default continuation options", func() {
+			factory := task.NewFactory(task.WithDefaultContinuationOptions(task.OnlyOnRanToCompletion()))
+			tasks := []task.Task{
+				factory.RunErrAction(func() error { return fmt.Errorf("error") }),
+			}
+			t := factory.ContinueWhenAll(tasks, func(task.Task) (interface{}, error) {
+				return 1, nil
+			})
+			t.Wait()
+			Expect(t.IsCanceled()).To(BeTrue())
+		})
+		It("runs on the default scheduler", func() {
+			scheduler := task.NewQueueScheduler()
+			factory := task.NewFactory(task.WithDefaultRunOptions(task.WithScheduler(scheduler)))
+			t := factory.ContinueWhenAll([]task.Task{task.Completed()}, func(task.Task) (interface{}, error) {
+				return 1, nil
+			})
+			Expect(t.IsCompleted()).To(BeFalse())
+			Expect(scheduler.Dequeue()).To(BeTrue())
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(1))
+		})
+		It("is canceled by the default cancellation", func() {
+			source := task.NewCancellationSource()
+			factory := task.NewFactory(task.WithDefaultRunOptions(task.WithCancellation(source)))
+			completion := task.NewCompletionSource()
+			count := 0
+			t := factory.ContinueWhenAll([]task.Task{completion.Task()}, func(task.Task) (interface{}, error) {
+				count++
+				return nil, nil
+			})
+			source.Cancel()
+			Expect(t.IsCanceled()).To(BeTrue())
+			completion.SetResult(1)
+			Expect(count).To(Equal(0))
+		})
+	})
+	Describe("ContinueWhenAny", func() {
+		It("continues after first task", func() {
+			factory := task.NewFactory()
+			tasks := []task.Task{
+				factory.RunFunc(func() interface{} { return 1 }),
+			}
+			t := factory.ContinueWhenAny(tasks, func(when task.Task) (interface{}, error) {
+				return when.Result().(task.WhenAnyResult).Index(), nil
+			})
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(0))
+		})
+		It("applies the default scheduler and cancellation", func() {
+			scheduler := task.NewQueueScheduler()
+			source := task.NewCancellationSource()
+			factory := task.NewFactory(task.WithDefaultRunOptions(task.WithScheduler(scheduler), task.WithCancellation(source)))
+			t := factory.ContinueWhenAny([]task.Task{task.Completed()}, func(task.Task) (interface{}, error) {
+				return 1, nil
+			})
+			Expect(t.IsCompleted()).To(BeFalse())
+			source.Cancel()
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(scheduler.Dequeue()).To(BeTrue())
+		})
+	})
+	Describe("Invoke", func() {
+		It("runs all actions", func() {
+			var count int32
+			action := func() { atomic.AddInt32(&count, 1) }
+			t := task.NewFactory().Invoke(action, action, action)
+			Expect(t.Wait()).To(BeNil())
+			Expect(atomic.LoadInt32(&count)).To(Equal(int32(3)))
+		})
+		It("runs all actions with ParallelInvoke", func() {
+			var count int32
+			action := func() { atomic.AddInt32(&count, 1) }
+			Expect(task.ParallelInvoke(action, action).Wait()).To(BeNil())
+			Expect(atomic.LoadInt32(&count)).To(Equal(int32(2)))
+		})
+	})
+})
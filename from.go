@@ -1,44 +1,63 @@
 package task
 
-// Completed returns a completed task in the StatusSuccess state
+import "context"
+
+// cached completed tasks, completed tasks are immutable so they can be shared
+var (
+	completedTask = fromOutcome(StatusSuccess, nil, nil)
+	trueTask      = fromOutcome(StatusSuccess, true, nil)
+	falseTask     = fromOutcome(StatusSuccess, false, nil)
+	canceledTask  = fromOutcome(StatusCanceled, nil, context.Canceled)
+)
+
+// Completed returns a completed task in the StatusSuccess state. The same task is returned on every call.
 func Completed() Task {
-	doneCh := make(chan struct{}, 1)
-	doneCh <- struct{}{}
-	close(doneCh)
-	return &task{
-		status: StatusSuccess,
-		doneCh: doneCh,
-	}
+	return completedTask
 }
 
-// FromResult returns a completed task in the StatusSuccess state with the given result
+// FromResult returns a completed task in the StatusSuccess state with the given result. Tasks with
+// a nil or boolean result are cached.
 func FromResult(result interface{}) Task {
-	doneCh := make(chan struct{}, 1)
-	doneCh <- struct{}{}
-	close(doneCh)
-	return &task{
-		result: result,
-		status: StatusSuccess,
-		doneCh: doneCh,
+	switch value := result.(type) {
+	case nil:
+		return completedTask
+	case bool:
+		if value {
+			return trueTask
+		}
+		return falseTask
 	}
+	return fromOutcome(StatusSuccess, result, nil)
 }
 
 // FromError returns a completed task in StatusFaulted state with the given error
 func FromError(err error) Task {
-	doneCh := make(chan struct{}, 1)
-	doneCh <- struct{}{}
-	close(doneCh)
-	return &task{
-		err:    err,
-		status: StatusFaulted,
-		doneCh: doneCh,
+	return fromOutcome(StatusFaulted, nil, err)
+}
+
+// FromCanceled returns a completed task in the StatusCanceled state with the given error. A nil
+// error returns a cached task canceled with context.Canceled.
+func FromCanceled(err error) Task {
+	if err == nil || err == context.Canceled {
+		return canceledTask
 	}
+	return fromOutcome(StatusCanceled, nil, err)
+}
+
+// fromOutcome creates a task that is already in the given terminal status. The task supports
+// subscriptions and continuations like any other task.
+func fromOutcome(status TaskStatus, result interface{}, err error) *task {
+	t := new(nil)
+	t.apply()
+	t.complete(status, result, err)
+	t.tracker.NotifyTerminal(status, result, err)
+	return t
 }
 
 // FromAction creates an unstarted task from the given action
 func FromAction(action Action) Task {
-	return new(func(i interface{}) (interface{}, error) {
+	return new(ignoreContext(func(i interface{}) (interface{}, error) {
 		action()
 		return nil, nil
-	})
+	}))
 }
@@ -0,0 +1,73 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("From", func() {
+	It("replays result to subscribers", func() {
+		observer := NewTestObserver()
+		task.FromResult(1).Subscribe(observer)
+		Expect(observer.nextCount).To(Equal(1))
+		Expect(observer.completedCount).To(Equal(1))
+	})
+	It("replays error to subscribers", func() {
+		observer := NewTestObserver()
+		task.FromError(fmt.Errorf("error")).Subscribe(observer)
+		Expect(observer.errorCount).To(Equal(1))
+		Expect(observer.completedCount).To(Equal(1))
+	})
+	It("replays cancellation to subscribers", func() {
+		observer := NewTestObserver()
+		task.FromCanceled(nil).Subscribe(observer)
+		Expect(observer.canceledCount).To(Equal(1))
+		Expect(observer.completedCount).To(Equal(1))
+	})
+	It("continues from result", func() {
+		t := task.FromResult(1).ContinueFunc(func(t task.Task) interface{} {
+			return t.Result().(int) + 1
+		})
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(2))
+	})
+	It("continues from error", func() {
+		t := task.FromError(fmt.Errorf("error")).ContinueAction(func(task.Task) {}, task.OnlyOnFaulted())
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.IsSuccess()).To(BeTrue())
+	})
+	It("continues from completed", func() {
+		t := task.Completed().ContinueFunc(func(task.Task) interface{} {
+			return 1
+		})
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(1))
+	})
+	It("can wait for all", func() {
+		t := task.WhenAll(task.FromResult(1), task.Completed(), task.FromResult(true))
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]interface{}{1, nil, true}))
+	})
+	It("creates canceled tasks", func() {
+		err := fmt.Errorf("canceled")
+		t := task.FromCanceled(err)
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(t.Wait()).To(Equal(err))
+		Expect(task.FromCanceled(nil).Error()).To(Equal(context.Canceled))
+	})
+	It("caches completed tasks", func() {
+		Expect(task.Completed()).To(BeIdenticalTo(task.Completed()))
+		Expect(task.FromResult(nil)).To(BeIdenticalTo(task.Completed()))
+		Expect(task.FromResult(true)).To(BeIdenticalTo(task.FromResult(true)))
+		Expect(task.FromResult(false)).To(BeIdenticalTo(task.FromResult(false)))
+		Expect(task.FromCanceled(nil)).To(BeIdenticalTo(task.FromCanceled(context.Canceled)))
+	})
+	It("does not cancel cached tasks", func() {
+		task.Completed().Cancel()
+		Expect(task.Completed().IsSuccess()).To(BeTrue())
+	})
+})
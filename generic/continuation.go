@@ -0,0 +1,37 @@
+package generic
+
+import "github.com/patrickhuber/go-task"
+
+// ContinueWith creates a continuation of the typed task that runs when the antecedent completes.
+// The continuation receives the antecedent as a Task[T] and produces a Task[U].
+func ContinueWith[T, U any](antecedent Task[T], f ContinueErrFunc[T, U], options ...task.ContinuationOption) Task[U] {
+	continuation := antecedent.Untyped().ContinueErrFunc(func(t task.Task) (interface{}, error) {
+		return f(From[T](t))
+	}, options...)
+	return From[U](continuation)
+}
+
+// ContinueWithTask creates a continuation of the typed task that starts another task. The returned
+// task completes when the task returned by the continuation completes, see task.Unwrap.
+func ContinueWithTask[T, U any](antecedent Task[T], f func(Task[T]) Task[U], options ...task.ContinuationOption) Task[U] {
+	proxy := antecedent.Untyped().ContinueWithTask(func(t task.Task) task.Task {
+		inner := f(From[T](t))
+		if inner == nil {
+			return nil
+		}
+		return inner.Untyped()
+	}, options...)
+	return From[U](proxy)
+}
+
+// Unwrap returns a proxy for a task whose result is another task, see task.Unwrap
+func Unwrap[T any](outer Task[Task[T]]) Task[T] {
+	untyped := outer.Untyped().Then(func(result interface{}) (interface{}, error) {
+		inner, _ := result.(Task[T])
+		if inner == nil {
+			return nil, nil
+		}
+		return inner.Untyped(), nil
+	})
+	return From[T](task.Unwrap(untyped))
+}
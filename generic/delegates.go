@@ -0,0 +1,5 @@
+package generic
+
+type ErrFunc[T any] func() (T, error)
+type ErrFuncWith[T any] func(interface{}) (T, error)
+type ContinueErrFunc[T, U any] func(Task[T]) (U, error)
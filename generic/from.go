@@ -0,0 +1,18 @@
+package generic
+
+import "github.com/patrickhuber/go-task"
+
+// FromResult returns a completed task in the StatusSuccess state with the given result
+func FromResult[T any](result T) Task[T] {
+	return From[T](task.FromResult(result))
+}
+
+// FromError returns a completed task in StatusFaulted state with the given error
+func FromError[T any](err error) Task[T] {
+	return From[T](task.FromError(err))
+}
+
+// FromCanceled returns a completed task in StatusCanceled state with the given error
+func FromCanceled[T any](err error) Task[T] {
+	return From[T](task.FromCanceled(err))
+}
@@ -0,0 +1,13 @@
+package generic_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGeneric(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Generic Suite")
+}
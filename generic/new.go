@@ -0,0 +1,13 @@
+package generic
+
+import "github.com/patrickhuber/go-task"
+
+// New creates a new unstarted typed task with the given delegate and run options
+func New[T any](f ErrFunc[T], options ...task.RunOption) Task[T] {
+	return From[T](task.NewErrFunc(untyped(f), options...))
+}
+
+// NewWith creates a new unstarted typed task with the given delegate and run options
+func NewWith[T any](f ErrFuncWith[T], options ...task.RunOption) Task[T] {
+	return From[T](task.NewErrFuncWith(untypedWith(f), options...))
+}
@@ -0,0 +1,26 @@
+package generic
+
+import "github.com/patrickhuber/go-task"
+
+// Run runs the given function with the supplied RunOptions and returns a typed task
+func Run[T any](f ErrFunc[T], options ...task.RunOption) Task[T] {
+	return From[T](task.RunErrFunc(untyped(f), options...))
+}
+
+// RunWith runs the given function with the supplied RunOptions and returns a typed task.
+// The state argument can be supplied with task.WithState(state) in the options parameter list.
+func RunWith[T any](f ErrFuncWith[T], options ...task.RunOption) Task[T] {
+	return From[T](task.RunErrFuncWith(untypedWith(f), options...))
+}
+
+func untyped[T any](f ErrFunc[T]) task.ErrFunc {
+	return func() (interface{}, error) {
+		return f()
+	}
+}
+
+func untypedWith[T any](f ErrFuncWith[T]) task.ErrFuncWith {
+	return func(state interface{}) (interface{}, error) {
+		return f(state)
+	}
+}
@@ -0,0 +1,58 @@
+package generic
+
+import (
+	"github.com/patrickhuber/go-task"
+)
+
+// Task represents a unit of work that produces a value of type T.
+type Task[T any] interface {
+	// Start executes the task. This is called by the scheduler to start the task.
+	Start()
+	// Wait will return immediately if the task is complete. It will block if the task is running.
+	Wait() error
+	// Result returns the typed result. It will not block and will return immediately.
+	Result() T
+	// Error returns the error It will not block and will return immediately.
+	Error() error
+	// IsCompleted returns true if the task is in success, faulted or canceled status
+	IsCompleted() bool
+	// IsFaulted returns true if the task is in the faulted status
+	IsFaulted() bool
+	// IsCanceled returns true if the task is in the canceled status
+	IsCanceled() bool
+	// IsSuccess returns true if the task was run to completion successfully
+	IsSuccess() bool
+	// Status returns the task status
+	Status() task.TaskStatus
+	// Untyped returns the underlying task.Task so it can be used with the untyped api
+	Untyped() task.Task
+}
+
+type typedTask[T any] struct {
+	task.Task
+}
+
+// From wraps the untyped task in a Task[T]. The result of the untyped task is converted
+// to T when Result is called. A result that is not a T is returned as the zero value of T.
+func From[T any](t task.Task) Task[T] {
+	return &typedTask[T]{
+		Task: t,
+	}
+}
+
+func (t *typedTask[T]) Result() T {
+	var zero T
+	value := t.Task.Result()
+	if value == nil {
+		return zero
+	}
+	result, ok := value.(T)
+	if !ok {
+		return zero
+	}
+	return result
+}
+
+func (t *typedTask[T]) Untyped() task.Task {
+	return t.Task
+}
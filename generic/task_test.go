@@ -0,0 +1,103 @@
+package generic_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/patrickhuber/go-task"
+	"github.com/patrickhuber/go-task/generic"
+)
+
+var _ = Describe("Task", func() {
+	It("can return result", func() {
+		t := generic.Run(func() (int, error) {
+			return 1, nil
+		})
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(1))
+	})
+	It("can return error", func() {
+		t := generic.Run(func() (int, error) {
+			return 0, fmt.Errorf("error")
+		})
+		Expect(t.Wait()).ToNot(BeNil())
+		Expect(t.IsFaulted()).To(BeTrue())
+		Expect(t.Result()).To(Equal(0))
+	})
+	It("can roundtrip state", func() {
+		t := generic.RunWith(func(state interface{}) (string, error) {
+			return state.(string), nil
+		}, task.WithState("state"))
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal("state"))
+	})
+	It("can create unstarted task", func() {
+		t := generic.New(func() (int, error) {
+			return 1, nil
+		})
+		Expect(t.Status()).To(Equal(task.StatusCreated))
+		t.Start()
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(1))
+	})
+	It("can wrap untyped task", func() {
+		t := generic.From[int](task.FromResult(1))
+		Expect(t.Result()).To(Equal(1))
+	})
+	It("returns zero value for mismatched type", func() {
+		t := generic.From[int](task.FromResult("one"))
+		Expect(t.Result()).To(Equal(0))
+	})
+	It("can be used with untyped api", func() {
+		t := generic.FromResult(1)
+		Expect(task.WhenAll(t.Untyped()).Wait()).To(BeNil())
+	})
+	Describe("ContinueWith", func() {
+		It("can change type", func() {
+			t := generic.FromResult(1)
+			c := generic.ContinueWith(t, func(t generic.Task[int]) (string, error) {
+				return fmt.Sprintf("%d", t.Result()+1), nil
+			})
+			Expect(c.Wait()).To(BeNil())
+			Expect(c.Result()).To(Equal("2"))
+		})
+	})
+	Describe("ContinueWithTask", func() {
+		It("completes with inner result", func() {
+			t := generic.FromResult(1)
+			c := generic.ContinueWithTask(t, func(t generic.Task[int]) generic.Task[string] {
+				return generic.FromResult(fmt.Sprintf("%d", t.Result()+1))
+			})
+			Expect(c.Wait()).To(BeNil())
+			Expect(c.Result()).To(Equal("2"))
+		})
+	})
+	Describe("Unwrap", func() {
+		It("completes with inner result", func() {
+			outer := generic.FromResult(generic.FromResult(1))
+			t := generic.Unwrap(outer)
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(1))
+		})
+	})
+	Describe("WhenAll", func() {
+		It("returns results in order", func() {
+			tasks := []generic.Task[int]{}
+			for i := 0; i < 3; i++ {
+				tasks = append(tasks, generic.RunWith(func(state interface{}) (int, error) {
+					return state.(int), nil
+				}, task.WithState(i)))
+			}
+			t := generic.WhenAll(tasks...)
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal([]int{0, 1, 2}))
+		})
+		It("returns error", func() {
+			t := generic.WhenAll(generic.FromResult(1), generic.FromError[int](fmt.Errorf("error")))
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.Result()).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,22 @@
+package generic
+
+import "github.com/patrickhuber/go-task"
+
+// WhenAll creates a task that completes when all tasks in the list complete. The result
+// is the slice of task results in the same order as the supplied tasks.
+func WhenAll[T any](tasks ...Task[T]) Task[[]T] {
+	untypedTasks := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		untypedTasks = append(untypedTasks, t.Untyped())
+	}
+	continuation := task.WhenAll(untypedTasks...).Then(func(result interface{}) (interface{}, error) {
+		untypedResults, _ := result.([]interface{})
+		results := make([]T, 0, len(untypedResults))
+		for _, r := range untypedResults {
+			value, _ := r.(T)
+			results = append(results, value)
+		}
+		return results, nil
+	})
+	return From[[]T](continuation)
+}
@@ -1,5 +1,7 @@
 package task
 
+import "context"
+
 // NewAction creates a new unstarted task with the given delegate and run options
 func NewAction(action Action, options ...RunOption) Task {
 	errFuncWith := func(interface{}) (interface{}, error) {
@@ -60,13 +62,24 @@ func NewErrFunc(f ErrFunc, options ...RunOption) Task {
 
 // NewErrFuncWith creates a new unstarted task with the given delegate and run options
 func NewErrFuncWith(errFuncWith ErrFuncWith, options ...RunOption) Task {
+	return NewContextErrFuncWith(ignoreContext(errFuncWith), options...)
+}
+
+// NewContextErrAction creates a new unstarted task with the given context aware delegate and run options
+func NewContextErrAction(contextErrAction ContextErrAction, options ...RunOption) Task {
+	delegate := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return nil, contextErrAction(ctx)
+	}
+	return NewContextErrFuncWith(delegate, options...)
+}
+
+// NewContextErrFuncWith creates a new unstarted task with the given context aware delegate and run options
+func NewContextErrFuncWith(contextErrFuncWith ContextErrFuncWith, options ...RunOption) Task {
 	// create the task
-	t := new(errFuncWith)
+	t := new(contextErrFuncWith)
 
 	// apply operations
-	for _, opt := range options {
-		opt(t)
-	}
+	t.apply(options...)
 
 	// return unstarted task
 	return t
@@ -1,6 +1,8 @@
 package task_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -62,4 +64,18 @@ var _ = Describe("New", func() {
 		Expect(t).ToNot(BeNil())
 		Expect(t.Status()).To(Equal(task.StatusCreated))
 	})
+	It("can create new ContextErrAction", func() {
+		t := task.NewContextErrAction(func(ctx context.Context) error {
+			return nil
+		})
+		Expect(t).ToNot(BeNil())
+		Expect(t.Status()).To(Equal(task.StatusCreated))
+	})
+	It("can create new ContextErrFuncWith", func() {
+		t := task.NewContextErrFuncWith(func(ctx context.Context, state interface{}) (interface{}, error) {
+			return nil, nil
+		}, task.WithState(1))
+		Expect(t).ToNot(BeNil())
+		Expect(t.Status()).To(Equal(task.StatusCreated))
+	})
 })
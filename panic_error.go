@@ -0,0 +1,55 @@
+package task
+
+import (
+	"fmt"
+)
+
+// PanicError is the error of a task whose delegate panicked
+type PanicError interface {
+	error
+	// Value returns the value passed to panic
+	Value() interface{}
+	// Stack returns the stack trace of the panicking routine
+	Stack() []byte
+	// Task returns the task that panicked
+	Task() Task
+}
+
+type panicError struct {
+	value interface{}
+	stack []byte
+	task  Task
+}
+
+// NewPanicError creates a PanicError for the given panic value, stack trace and task
+func NewPanicError(value interface{}, stack []byte, t Task) PanicError {
+	return &panicError{
+		value: value,
+		stack: stack,
+		task:  t,
+	}
+}
+
+func (err *panicError) Error() string {
+	return fmt.Sprintf("task panic: %v\n%s", err.value, err.stack)
+}
+
+// Unwrap returns the panic value if it is an error
+func (err *panicError) Unwrap() error {
+	if e, ok := err.value.(error); ok {
+		return e
+	}
+	return nil
+}
+
+func (err *panicError) Value() interface{} {
+	return err.value
+}
+
+func (err *panicError) Stack() []byte {
+	return err.stack
+}
+
+func (err *panicError) Task() Task {
+	return err.task
+}
@@ -0,0 +1,66 @@
+package task_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("PanicError", func() {
+	It("faults the task", func() {
+		t := task.RunAction(func() {
+			panic("panic")
+		})
+		err := t.Wait()
+		Expect(err).ToNot(BeNil())
+		Expect(t.IsFaulted()).To(BeTrue())
+
+		panicErr, ok := err.(task.PanicError)
+		Expect(ok).To(BeTrue())
+		Expect(panicErr.Value()).To(Equal("panic"))
+		Expect(panicErr.Stack()).ToNot(BeEmpty())
+		Expect(panicErr.Task()).To(Equal(t))
+	})
+	It("unwraps error values", func() {
+		expected := fmt.Errorf("error")
+		t := task.RunAction(func() {
+			panic(expected)
+		})
+		err := t.Wait()
+		Expect(errors.Is(err, expected)).To(BeTrue())
+	})
+	It("notifies observers", func() {
+		observer := NewTestObserver()
+		source := task.NewCompletionSource()
+		t := source.Task().ContinueAction(func(task.Task) {
+			panic("panic")
+		}, task.ExecuteSynchronously())
+		t.Subscribe(observer)
+		source.SetResult(1)
+		Expect(t.IsFaulted()).To(BeTrue())
+		Expect(observer.errorCount).To(Equal(1))
+		Expect(observer.completedCount).To(Equal(1))
+	})
+	It("flows into aggregate error", func() {
+		t := task.WhenAll(
+			task.RunAction(func() { panic("first") }),
+			task.RunAction(func() {}),
+		)
+		err := t.Wait()
+		Expect(err).ToNot(BeNil())
+		aggregate, ok := err.(task.AggregateError)
+		Expect(ok).To(BeTrue())
+		Expect(len(aggregate.Errors())).To(Equal(1))
+		var panicErr task.PanicError
+		Expect(errors.As(aggregate.Errors()[0], &panicErr)).To(BeTrue())
+	})
+	It("can propagate panics", func() {
+		t := task.NewAction(func() {
+			panic("panic")
+		}, task.WithPanicRecovery(false))
+		Expect(t.Start).To(PanicWith("panic"))
+	})
+})
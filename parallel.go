@@ -0,0 +1,270 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelOptions configures ParallelFor and ParallelForEach
+type ParallelOptions struct {
+	// MaxDegreeOfParallelism is the maximum number of iterations that run concurrently. Values less
+	// than one use runtime.GOMAXPROCS(0).
+	MaxDegreeOfParallelism int
+	// Scheduler runs the loop workers. A nil Scheduler uses the DefaultScheduler.
+	Scheduler Scheduler
+	// Context cancels the loop. Iterations that have not started when the context is done are skipped
+	// and the loop task is canceled.
+	Context context.Context
+}
+
+// ParallelForBody is invoked for each index of ParallelFor
+type ParallelForBody func(ctx context.Context, index int, state LoopState) error
+
+// ParallelForEachBody is invoked for each item of ParallelForEach
+type ParallelForEachBody func(ctx context.Context, index int, item interface{}, state LoopState) error
+
+// LoopState allows an iteration of a parallel loop to end the loop early
+type LoopState interface {
+	// Break stops the loop from starting iterations after the current index. Iterations with a lower
+	// index still run.
+	Break()
+	// Stop stops the loop from starting any new iterations
+	Stop()
+	// IsStopped returns true if any iteration called Stop
+	IsStopped() bool
+	// ShouldExitCurrentIteration returns true if the loop was stopped, canceled or failed, or if an
+	// iteration with a lower index called Break
+	ShouldExitCurrentIteration() bool
+	// LowestBreakIteration returns the lowest index that called Break. The second return value is false
+	// if no iteration called Break.
+	LowestBreakIteration() (int, bool)
+}
+
+// LoopResult is the result of a task created by ParallelFor or ParallelForEach
+type LoopResult interface {
+	// IsCompleted returns true if every iteration ran, that is no iteration called Break or Stop
+	IsCompleted() bool
+	// LowestBreakIteration returns the lowest index that called Break. The second return value is false
+	// if no iteration called Break.
+	LowestBreakIteration() (int, bool)
+}
+
+// ParallelFor runs the body for each index from inclusive to exclusive on concurrent workers. The
+// task completes when all workers finish. If any iteration returns an error no new iterations are
+// started and the task faults with an AggregateError of the iteration errors. On success the result is
+// a LoopResult.
+func ParallelFor(from int, to int, body ParallelForBody, options ParallelOptions) Task {
+	loop := newParallelLoop(from, to, body, options)
+	loop.start()
+	return loop.task
+}
+
+// ParallelForEach runs the body for each item of the list on concurrent workers, see ParallelFor
+func ParallelForEach(items []interface{}, body ParallelForEachBody, options ParallelOptions) Task {
+	return ParallelFor(0, len(items), func(ctx context.Context, index int, state LoopState) error {
+		return body(ctx, index, items[index], state)
+	}, options)
+}
+
+type parallelLoop struct {
+	task    *task
+	from    int
+	count   int64
+	body    ParallelForBody
+	workers int
+	// next is the offset of the next iteration to run
+	next     int64
+	executed int64
+	// lowestBreak is the lowest offset that called Break or math.MaxInt64
+	lowestBreak int64
+	stopped     int32
+	failed      int32
+	remaining   int32
+	errs        []error
+	mutex       sync.Mutex
+}
+
+func newParallelLoop(from int, to int, body ParallelForBody, options ParallelOptions) *parallelLoop {
+	t := new(nil)
+	if options.Scheduler != nil {
+		t.scheduler = options.Scheduler
+	}
+	if options.Context != nil {
+		t.apply(WithContext(options.Context))
+	} else {
+		t.apply()
+	}
+
+	count := int64(0)
+	if to > from {
+		count = int64(to) - int64(from)
+	}
+	workers := options.MaxDegreeOfParallelism
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if int64(workers) > count {
+		workers = int(count)
+	}
+	return &parallelLoop{
+		task:        t,
+		from:        from,
+		count:       count,
+		body:        body,
+		workers:     workers,
+		lowestBreak: math.MaxInt64,
+		remaining:   int32(workers),
+	}
+}
+
+func (l *parallelLoop) start() {
+	l.task.transitionTo(StatusRunning)
+	if l.workers == 0 {
+		l.complete()
+		return
+	}
+	for i := 0; i < l.workers; i++ {
+		worker := new(func(ctx context.Context, _ interface{}) (interface{}, error) {
+			return nil, l.work(ctx)
+		})
+		worker.apply(WithContext(l.task.context), WithScheduler(l.task.scheduler))
+		// subscribe before queuing so the completion of the worker is never missed
+		worker.Subscribe(NewObserver(nil, func() {
+			l.done(worker)
+		}, nil, nil))
+		worker.queue()
+	}
+}
+
+// work runs iterations until the range is exhausted or the loop ends early
+func (l *parallelLoop) work(ctx context.Context) error {
+	for {
+		if ctx.Err() != nil || l.exiting() {
+			return nil
+		}
+		offset := atomic.AddInt64(&l.next, 1) - 1
+		if offset >= l.count || offset > atomic.LoadInt64(&l.lowestBreak) {
+			return nil
+		}
+		state := &loopState{
+			loop:   l,
+			offset: offset,
+		}
+		err := l.body(ctx, l.from+int(offset), state)
+		switch {
+		case err == nil:
+			atomic.AddInt64(&l.executed, 1)
+		case ctx.Err() != nil && errors.Is(err, ctx.Err()):
+			// the iteration observed the cancellation of the loop
+		default:
+			l.fail(err)
+		}
+	}
+}
+
+func (l *parallelLoop) exiting() bool {
+	return atomic.LoadInt32(&l.stopped) != 0 || atomic.LoadInt32(&l.failed) != 0
+}
+
+func (l *parallelLoop) fail(err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.errs = append(l.errs, err)
+	atomic.StoreInt32(&l.failed, 1)
+}
+
+// done is called when a worker completes, the last worker completes the loop task
+func (l *parallelLoop) done(worker Task) {
+	// a panic in the body faults the worker
+	if worker.IsFaulted() {
+		l.fail(worker.Error())
+	}
+	if atomic.AddInt32(&l.remaining, -1) != 0 {
+		return
+	}
+	l.complete()
+}
+
+func (l *parallelLoop) complete() {
+	l.mutex.Lock()
+	errs := l.errs
+	l.mutex.Unlock()
+
+	if len(errs) > 0 {
+		// an iteration error can be an AggregateError of another task, do not append to it
+		l.task.setFaulted(newAggregateError(errs...))
+		return
+	}
+	if err := l.task.context.Err(); err != nil && atomic.LoadInt64(&l.executed) < l.count {
+		l.task.setCanceled(err)
+		return
+	}
+	index, broken := l.lowestBreakIteration()
+	l.task.setSuccess(&loopResult{
+		completed:   atomic.LoadInt32(&l.stopped) == 0 && !broken,
+		lowestBreak: index,
+		broken:      broken,
+	})
+}
+
+func (l *parallelLoop) lowestBreakIteration() (int, bool) {
+	offset := atomic.LoadInt64(&l.lowestBreak)
+	if offset == math.MaxInt64 {
+		return 0, false
+	}
+	return l.from + int(offset), true
+}
+
+type loopState struct {
+	loop   *parallelLoop
+	offset int64
+}
+
+func (s *loopState) Break() {
+	for {
+		lowest := atomic.LoadInt64(&s.loop.lowestBreak)
+		if s.offset >= lowest {
+			return
+		}
+		if atomic.CompareAndSwapInt64(&s.loop.lowestBreak, lowest, s.offset) {
+			return
+		}
+	}
+}
+
+func (s *loopState) Stop() {
+	atomic.StoreInt32(&s.loop.stopped, 1)
+}
+
+func (s *loopState) IsStopped() bool {
+	return atomic.LoadInt32(&s.loop.stopped) != 0
+}
+
+func (s *loopState) ShouldExitCurrentIteration() bool {
+	if s.loop.exiting() || s.loop.task.context.Err() != nil {
+		return true
+	}
+	return s.offset > atomic.LoadInt64(&s.loop.lowestBreak)
+}
+
+func (s *loopState) LowestBreakIteration() (int, bool) {
+	return s.loop.lowestBreakIteration()
+}
+
+type loopResult struct {
+	completed   bool
+	lowestBreak int
+	broken      bool
+}
+
+func (r *loopResult) IsCompleted() bool {
+	return r.completed
+}
+
+func (r *loopResult) LowestBreakIteration() (int, bool) {
+	return r.lowestBreak, r.broken
+}
@@ -0,0 +1,175 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("Parallel", func() {
+	Describe("ParallelFor", func() {
+		It("runs each index", func() {
+			var mutex sync.Mutex
+			indexes := map[int]bool{}
+			t := task.ParallelFor(2, 12, func(ctx context.Context, index int, state task.LoopState) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				indexes[index] = true
+				return nil
+			}, task.ParallelOptions{})
+			Expect(t.Wait()).To(BeNil())
+			Expect(len(indexes)).To(Equal(10))
+			Expect(indexes[2]).To(BeTrue())
+			Expect(indexes[11]).To(BeTrue())
+			result, ok := t.Result().(task.LoopResult)
+			Expect(ok).To(BeTrue())
+			Expect(result.IsCompleted()).To(BeTrue())
+			_, broken := result.LowestBreakIteration()
+			Expect(broken).To(BeFalse())
+		})
+		It("succeeds with empty range", func() {
+			t := task.ParallelFor(5, 5, func(ctx context.Context, index int, state task.LoopState) error {
+				return fmt.Errorf("error")
+			}, task.ParallelOptions{})
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.IsSuccess()).To(BeTrue())
+		})
+		It("limits degree of parallelism", func() {
+			var running, max int32
+			t := task.ParallelFor(0, 50, func(ctx context.Context, index int, state task.LoopState) error {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					observed := atomic.LoadInt32(&max)
+					if current <= observed || atomic.CompareAndSwapInt32(&max, observed, current) {
+						break
+					}
+				}
+				atomic.AddInt32(&running, -1)
+				return nil
+			}, task.ParallelOptions{MaxDegreeOfParallelism: 2})
+			Expect(t.Wait()).To(BeNil())
+			Expect(atomic.LoadInt32(&max)).To(BeNumerically("<=", 2))
+		})
+		It("runs lower iterations after break", func() {
+			var mutex sync.Mutex
+			indexes := map[int]bool{}
+			t := task.ParallelFor(0, 100, func(ctx context.Context, index int, state task.LoopState) error {
+				mutex.Lock()
+				indexes[index] = true
+				mutex.Unlock()
+				if index == 10 {
+					state.Break()
+				}
+				return nil
+			}, task.ParallelOptions{MaxDegreeOfParallelism: 4})
+			Expect(t.Wait()).To(BeNil())
+			for i := 0; i <= 10; i++ {
+				Expect(indexes[i]).To(BeTrue())
+			}
+			result := t.Result().(task.LoopResult)
+			Expect(result.IsCompleted()).To(BeFalse())
+			lowest, broken := result.LowestBreakIteration()
+			Expect(broken).To(BeTrue())
+			Expect(lowest).To(Equal(10))
+		})
+		It("stops new iterations", func() {
+			var count int32
+			t := task.ParallelFor(0, 1000, func(ctx context.Context, index int, state task.LoopState) error {
+				atomic.AddInt32(&count, 1)
+				state.Stop()
+				Expect(state.IsStopped()).To(BeTrue())
+				Expect(state.ShouldExitCurrentIteration()).To(BeTrue())
+				return nil
+			}, task.ParallelOptions{MaxDegreeOfParallelism: 2})
+			Expect(t.Wait()).To(BeNil())
+			Expect(atomic.LoadInt32(&count)).To(BeNumerically("<=", 2))
+			Expect(t.Result().(task.LoopResult).IsCompleted()).To(BeFalse())
+		})
+		It("faults with iteration errors", func() {
+			t := task.ParallelFor(0, 10, func(ctx context.Context, index int, state task.LoopState) error {
+				if index == 3 {
+					return fmt.Errorf("error")
+				}
+				return nil
+			}, task.ParallelOptions{MaxDegreeOfParallelism: 1})
+			err := t.Wait()
+			Expect(t.IsFaulted()).To(BeTrue())
+			aggregate, ok := err.(task.AggregateError)
+			Expect(ok).To(BeTrue())
+			Expect(len(aggregate.Errors())).To(Equal(1))
+		})
+		It("does not modify an aggregate returned by an iteration", func() {
+			when := task.WhenAll(task.FromError(fmt.Errorf("one")), task.FromError(fmt.Errorf("two")))
+			var started sync.WaitGroup
+			started.Add(2)
+			t := task.ParallelFor(0, 2, func(ctx context.Context, index int, state task.LoopState) error {
+				started.Done()
+				started.Wait()
+				return when.Wait()
+			}, task.ParallelOptions{MaxDegreeOfParallelism: 2})
+			err := t.Wait()
+			Expect(err.(task.AggregateError).Errors()).To(HaveLen(2))
+			Expect(when.Error().(task.AggregateError).Errors()).To(HaveLen(2))
+		})
+		It("faults on panic", func() {
+			t := task.ParallelFor(0, 10, func(ctx context.Context, index int, state task.LoopState) error {
+				panic("panic")
+			}, task.ParallelOptions{})
+			t.Wait()
+			Expect(t.IsFaulted()).To(BeTrue())
+		})
+		It("cancels with context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			var count int32
+			t := task.ParallelFor(0, 1000, func(ctx context.Context, index int, state task.LoopState) error {
+				if atomic.AddInt32(&count, 1) == 5 {
+					cancel()
+				}
+				return nil
+			}, task.ParallelOptions{MaxDegreeOfParallelism: 1, Context: ctx})
+			t.Wait()
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(atomic.LoadInt32(&count)).To(Equal(int32(5)))
+		})
+		It("completes after running iterations when canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			var started int32
+			release := make(chan struct{})
+			t := task.ParallelFor(0, 4, func(ctx context.Context, index int, state task.LoopState) error {
+				atomic.AddInt32(&started, 1)
+				<-release
+				return nil
+			}, task.ParallelOptions{MaxDegreeOfParallelism: 4, Context: ctx})
+			Eventually(func() int32 { return atomic.LoadInt32(&started) }).Should(Equal(int32(4)))
+			cancel()
+			Consistently(t.Status, "50ms").Should(Equal(task.StatusRunning))
+			close(release)
+			Eventually(t.Status).Should(Equal(task.StatusSuccess))
+		})
+		It("runs on scheduler", func() {
+			scheduler := task.NewPoolScheduler(2, 10)
+			defer scheduler.Shutdown(context.Background())
+			t := task.ParallelFor(0, 10, func(ctx context.Context, index int, state task.LoopState) error {
+				return nil
+			}, task.ParallelOptions{Scheduler: scheduler})
+			Expect(t.Wait()).To(BeNil())
+		})
+	})
+	Describe("ParallelForEach", func() {
+		It("runs each item", func() {
+			items := []interface{}{1, 2, 3, 4}
+			var sum int32
+			t := task.ParallelForEach(items, func(ctx context.Context, index int, item interface{}, state task.LoopState) error {
+				atomic.AddInt32(&sum, int32(item.(int)))
+				return nil
+			}, task.ParallelOptions{})
+			Expect(t.Wait()).To(BeNil())
+			Expect(atomic.LoadInt32(&sum)).To(Equal(int32(10)))
+		})
+	})
+})
@@ -0,0 +1,222 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrSchedulerFull faults tasks rejected because the scheduler queue is full
+var ErrSchedulerFull = errors.New("scheduler queue is full")
+
+// ErrSchedulerShutdown faults tasks queued after the scheduler is shut down
+var ErrSchedulerShutdown = errors.New("scheduler is shut down")
+
+// OverflowPolicy determines what happens to a task queued on a full PoolScheduler
+type OverflowPolicy string
+
+const (
+	// OverflowBlock blocks the caller of Queue until there is space in the queue or the scheduler is
+	// shut down, in which case the task faults with ErrSchedulerShutdown. A task queued from a worker of
+	// the pool, such as a continuation of a task running on the pool, runs inline instead because a
+	// blocked worker can not drain the queue.
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowReject faults the task with ErrSchedulerFull
+	OverflowReject OverflowPolicy = "reject"
+	// OverflowRunInline runs the task on the routine calling Queue
+	OverflowRunInline OverflowPolicy = "inline"
+)
+
+// PoolScheduler runs tasks on a fixed number of worker routines
+type PoolScheduler interface {
+	Scheduler
+	// Shutdown stops accepting tasks and waits for queued tasks to drain. If the context is done
+	// before the queue drains, the remaining tasks are canceled and the context error is returned.
+	Shutdown(ctx context.Context) error
+}
+
+type PoolOption func(s *poolScheduler)
+
+// WithOverflowPolicy sets the overflow policy of the pool scheduler. The default is OverflowBlock.
+func WithOverflowPolicy(policy OverflowPolicy) PoolOption {
+	return func(s *poolScheduler) {
+		s.overflow = policy
+	}
+}
+
+type poolScheduler struct {
+	queue    chan Task
+	overflow OverflowPolicy
+	shutdown bool
+	// closing is closed when Shutdown is called and releases callers blocked on a full queue
+	closing     chan struct{}
+	closingOnce sync.Once
+	abandoned   int32
+	workers     sync.WaitGroup
+	mutex       sync.RWMutex
+	// running holds the tasks the workers are running, see queuedFromWorker
+	running      map[Task]struct{}
+	runningMutex sync.Mutex
+}
+
+// NewPoolScheduler creates a scheduler with the given number of workers and queue capacity.
+// Continuations of tasks running on the pool, and tasks created with the context of their delegates,
+// do not block a worker on a full queue. Delegates that wait for other tasks in the same pool can still
+// deadlock when all workers are busy.
+func NewPoolScheduler(workers int, queueCapacity int, options ...PoolOption) PoolScheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueCapacity < 0 {
+		queueCapacity = 0
+	}
+	s := &poolScheduler{
+		queue:    make(chan Task, queueCapacity),
+		overflow: OverflowBlock,
+		closing:  make(chan struct{}),
+		running:  map[Task]struct{}{},
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	s.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go s.work()
+	}
+	return s
+}
+
+func (s *poolScheduler) work() {
+	defer s.workers.Done()
+	for t := range s.queue {
+		if atomic.LoadInt32(&s.abandoned) != 0 {
+			t.Cancel()
+			continue
+		}
+		s.setRunning(t, true)
+		t.Start()
+		s.setRunning(t, false)
+	}
+}
+
+func (s *poolScheduler) setRunning(t Task, running bool) {
+	s.runningMutex.Lock()
+	defer s.runningMutex.Unlock()
+	if running {
+		s.running[t] = struct{}{}
+		return
+	}
+	delete(s.running, t)
+}
+
+// queuedFromWorker returns true if the task is queued by a task running on a worker of the pool, that
+// is the task is a continuation of the running task or it was created with the context of its delegate
+func (s *poolScheduler) queuedFromWorker(t Task) bool {
+	current, ok := t.(*task)
+	if !ok {
+		return false
+	}
+	s.runningMutex.Lock()
+	defer s.runningMutex.Unlock()
+	for {
+		if parent, ok := current.context.Value(currentTaskKey{}).(*task); ok {
+			if _, running := s.running[parent]; running {
+				return true
+			}
+		}
+		// continuations that execute synchronously are queued by the antecedent of their antecedent
+		antecedent, ok := current.antecedent.(*task)
+		if !ok {
+			return false
+		}
+		if _, running := s.running[antecedent]; running {
+			return true
+		}
+		current = antecedent
+	}
+}
+
+func (s *poolScheduler) Queue(t Task) {
+	// do not schedule completed tasks
+	if t.IsCompleted() {
+		return
+	}
+	queued, err := s.enqueue(t)
+	if queued {
+		return
+	}
+	if err != nil {
+		fault(t, err)
+		return
+	}
+
+	// the queue is full, enqueue only returns here with OverflowBlock for tasks queued from a worker
+	switch s.overflow {
+	case OverflowRunInline, OverflowBlock:
+		t.Start()
+	default:
+		fault(t, ErrSchedulerFull)
+	}
+}
+
+// enqueue adds the task to the queue. It returns false if the queue is full or shut down.
+func (s *poolScheduler) enqueue(t Task) (bool, error) {
+	// the read lock prevents the queue from closing while sending
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.shutdown {
+		return false, ErrSchedulerShutdown
+	}
+	select {
+	case s.queue <- t:
+		return true, nil
+	default:
+	}
+	if s.overflow != OverflowBlock || s.queuedFromWorker(t) {
+		return false, nil
+	}
+	select {
+	case s.queue <- t:
+		return true, nil
+	case <-s.closing:
+		return false, ErrSchedulerShutdown
+	}
+}
+
+func (s *poolScheduler) Shutdown(ctx context.Context) error {
+	// release blocked callers of Queue so they give up the read lock
+	s.closingOnce.Do(func() {
+		close(s.closing)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.mutex.Lock()
+		if !s.shutdown {
+			s.shutdown = true
+			close(s.queue)
+		}
+		s.mutex.Unlock()
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// cancel the tasks remaining in the queue
+		atomic.StoreInt32(&s.abandoned, 1)
+		return ctx.Err()
+	}
+}
+
+// fault transitions the task to faulted if it is a task created by this package, otherwise the task is canceled
+func fault(t Task, err error) {
+	if c, ok := t.(interface{ setFaulted(error) bool }); ok {
+		c.setFaulted(err)
+		return
+	}
+	t.Cancel()
+}
@@ -0,0 +1,190 @@
+package task_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("PoolScheduler", func() {
+	It("runs tasks", func() {
+		scheduler := task.NewPoolScheduler(2, 10)
+		defer scheduler.Shutdown(context.Background())
+		tasks := []task.Task{}
+		for i := 0; i < 10; i++ {
+			tasks = append(tasks, task.RunFuncWith(func(state interface{}) interface{} {
+				return state
+			}, task.WithState(i), task.WithScheduler(scheduler)))
+		}
+		Expect(task.WhenAll(tasks...).Wait()).To(BeNil())
+		for i, t := range tasks {
+			Expect(t.Result()).To(Equal(i))
+		}
+	})
+	It("limits parallelism", func() {
+		workers := 3
+		scheduler := task.NewPoolScheduler(workers, 100)
+		defer scheduler.Shutdown(context.Background())
+		var running, max int32
+		tasks := []task.Task{}
+		for i := 0; i < 30; i++ {
+			tasks = append(tasks, task.RunAction(func() {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					observed := atomic.LoadInt32(&max)
+					if current <= observed || atomic.CompareAndSwapInt32(&max, observed, current) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			}, task.WithScheduler(scheduler)))
+		}
+		Expect(task.WhenAll(tasks...).Wait()).To(BeNil())
+		Expect(atomic.LoadInt32(&max)).To(BeNumerically("<=", workers))
+	})
+	Describe("overflow", func() {
+		var (
+			release chan struct{}
+			blocker task.Task
+		)
+		BeforeEach(func() {
+			release = make(chan struct{})
+		})
+		// occupy the only worker and fill the queue
+		fill := func(scheduler task.Scheduler) {
+			started := make(chan struct{})
+			blocker = task.RunAction(func() {
+				close(started)
+				<-release
+			}, task.WithScheduler(scheduler))
+			<-started
+			task.RunAction(func() {}, task.WithScheduler(scheduler))
+		}
+		It("rejects", func() {
+			scheduler := task.NewPoolScheduler(1, 1, task.WithOverflowPolicy(task.OverflowReject))
+			defer scheduler.Shutdown(context.Background())
+			fill(scheduler)
+			t := task.RunAction(func() {}, task.WithScheduler(scheduler))
+			Expect(t.Wait()).To(Equal(task.ErrSchedulerFull))
+			Expect(t.IsFaulted()).To(BeTrue())
+			close(release)
+			Expect(blocker.Wait()).To(BeNil())
+		})
+		It("runs inline", func() {
+			scheduler := task.NewPoolScheduler(1, 1, task.WithOverflowPolicy(task.OverflowRunInline))
+			defer scheduler.Shutdown(context.Background())
+			fill(scheduler)
+			t := task.RunAction(func() {}, task.WithScheduler(scheduler))
+			Expect(t.IsSuccess()).To(BeTrue())
+			close(release)
+			Expect(blocker.Wait()).To(BeNil())
+		})
+		It("blocks", func() {
+			scheduler := task.NewPoolScheduler(1, 1)
+			defer scheduler.Shutdown(context.Background())
+			fill(scheduler)
+			var wg sync.WaitGroup
+			wg.Add(1)
+			var t task.Task
+			go func() {
+				defer wg.Done()
+				t = task.RunAction(func() {}, task.WithScheduler(scheduler))
+			}()
+			Consistently(blocker.IsCompleted).Should(BeFalse())
+			close(release)
+			wg.Wait()
+			Expect(t.Wait()).To(BeNil())
+		})
+		It("does not block a worker queueing a continuation", func() {
+			scheduler := task.NewPoolScheduler(1, 1)
+			defer scheduler.Shutdown(context.Background())
+			fill(scheduler)
+			continuation := blocker.ContinueAction(func(task.Task) {}, task.RunOnScheduler(scheduler))
+			close(release)
+			Expect(continuation.Wait()).To(BeNil())
+			Expect(blocker.Wait()).To(BeNil())
+		})
+		It("does not block a worker queueing a task with the delegate context", func() {
+			scheduler := task.NewPoolScheduler(1, 1)
+			defer scheduler.Shutdown(context.Background())
+			started := make(chan struct{})
+			t := task.RunContextErrAction(func(ctx context.Context) error {
+				close(started)
+				<-release
+				return task.RunAction(func() {}, task.WithContext(ctx), task.WithScheduler(scheduler)).Wait()
+			}, task.WithScheduler(scheduler))
+			<-started
+			queued := task.RunAction(func() {}, task.WithScheduler(scheduler))
+			close(release)
+			Expect(t.Wait()).To(BeNil())
+			Expect(queued.Wait()).To(BeNil())
+		})
+	})
+	Describe("Shutdown", func() {
+		It("drains queued tasks", func() {
+			scheduler := task.NewPoolScheduler(1, 10)
+			var count int32
+			for i := 0; i < 5; i++ {
+				task.RunAction(func() {
+					atomic.AddInt32(&count, 1)
+				}, task.WithScheduler(scheduler))
+			}
+			Expect(scheduler.Shutdown(context.Background())).To(BeNil())
+			Expect(atomic.LoadInt32(&count)).To(Equal(int32(5)))
+		})
+		It("rejects tasks after shutdown", func() {
+			scheduler := task.NewPoolScheduler(1, 10)
+			Expect(scheduler.Shutdown(context.Background())).To(BeNil())
+			t := task.RunAction(func() {}, task.WithScheduler(scheduler))
+			Expect(t.Wait()).To(Equal(task.ErrSchedulerShutdown))
+		})
+		It("abandons pending tasks", func() {
+			scheduler := task.NewPoolScheduler(1, 10)
+			release := make(chan struct{})
+			started := make(chan struct{})
+			task.RunAction(func() {
+				close(started)
+				<-release
+			}, task.WithScheduler(scheduler))
+			<-started
+			pending := task.RunAction(func() {}, task.WithScheduler(scheduler))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			Expect(scheduler.Shutdown(ctx)).To(Equal(context.DeadlineExceeded))
+			close(release)
+			Expect(pending.Wait()).ToNot(BeNil())
+			Expect(pending.IsCanceled()).To(BeTrue())
+		})
+		It("releases tasks blocked on a full queue", func() {
+			scheduler := task.NewPoolScheduler(1, 1)
+			release := make(chan struct{})
+			started := make(chan struct{})
+			task.RunAction(func() {
+				close(started)
+				<-release
+			}, task.WithScheduler(scheduler))
+			<-started
+			task.RunAction(func() {}, task.WithScheduler(scheduler))
+			blocked := make(chan task.Task)
+			go func() {
+				blocked <- task.RunAction(func() {}, task.WithScheduler(scheduler))
+			}()
+			Consistently(blocked).ShouldNot(Receive())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			Expect(scheduler.Shutdown(ctx)).To(Equal(context.DeadlineExceeded))
+			var t task.Task
+			Eventually(blocked).Should(Receive(&t))
+			Expect(t.Wait()).To(Equal(task.ErrSchedulerShutdown))
+			close(release)
+		})
+	})
+})
@@ -0,0 +1,182 @@
+package task
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// PriorityScheduler runs the highest priority task next on a fixed number of worker routines.
+// Task priority is set with the WithPriority run option.
+type PriorityScheduler interface {
+	Scheduler
+	// Shutdown stops accepting tasks and waits for queued tasks to drain. If the context is done
+	// before the queue drains, the remaining tasks are canceled and the context error is returned.
+	Shutdown(ctx context.Context) error
+}
+
+type PriorityOption func(s *priorityScheduler)
+
+// WithAging raises the effective priority of a waiting task by one for every interval it waits
+// which prevents starvation of low priority tasks. The default interval is one second, zero disables aging.
+func WithAging(interval time.Duration) PriorityOption {
+	return func(s *priorityScheduler) {
+		s.aging = interval
+	}
+}
+
+// WithPrioritySchedulerClock sets the clock used to measure how long a task waits. The default is the DefaultClock.
+func WithPrioritySchedulerClock(clock Clock) PriorityOption {
+	return func(s *priorityScheduler) {
+		s.clock = clock
+	}
+}
+
+type priorityScheduler struct {
+	mutex     sync.Mutex
+	ready     *sync.Cond
+	queue     priorityQueue
+	aging     time.Duration
+	clock     Clock
+	sequence  uint64
+	shutdown  bool
+	abandoned bool
+	workers   sync.WaitGroup
+}
+
+// NewPriorityScheduler creates a priority scheduler with the given number of workers
+func NewPriorityScheduler(workers int, options ...PriorityOption) PriorityScheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	s := &priorityScheduler{
+		queue: priorityQueue{},
+		aging: time.Second,
+		clock: DefaultClock(),
+	}
+	s.ready = sync.NewCond(&s.mutex)
+	for _, opt := range options {
+		opt(s)
+	}
+	s.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go s.work()
+	}
+	return s
+}
+
+func (s *priorityScheduler) Queue(t Task) {
+	// do not schedule completed tasks
+	if t.IsCompleted() {
+		return
+	}
+	s.mutex.Lock()
+	if s.shutdown {
+		s.mutex.Unlock()
+		fault(t, ErrSchedulerShutdown)
+		return
+	}
+	s.sequence++
+	heap.Push(&s.queue, &priorityItem{
+		task:     t,
+		score:    s.score(t.Priority()),
+		sequence: s.sequence,
+	})
+	s.mutex.Unlock()
+	s.ready.Signal()
+}
+
+// score orders tasks by priority. With aging the enqueue time is subtracted so that waiting
+// one aging interval is worth one priority level. Because every task ages at the same rate
+// the score never needs to be recomputed.
+func (s *priorityScheduler) score(priority int) int64 {
+	if s.aging <= 0 {
+		return int64(priority)
+	}
+	return int64(priority)*int64(s.aging) - s.clock.Now().UnixNano()
+}
+
+func (s *priorityScheduler) work() {
+	defer s.workers.Done()
+	for {
+		s.mutex.Lock()
+		for len(s.queue) == 0 && !s.shutdown {
+			s.ready.Wait()
+		}
+		if len(s.queue) == 0 {
+			s.mutex.Unlock()
+			return
+		}
+		item := heap.Pop(&s.queue).(*priorityItem)
+		abandoned := s.abandoned
+		s.mutex.Unlock()
+
+		if abandoned {
+			item.task.Cancel()
+			continue
+		}
+		item.task.Start()
+	}
+}
+
+func (s *priorityScheduler) Shutdown(ctx context.Context) error {
+	s.mutex.Lock()
+	s.shutdown = true
+	s.mutex.Unlock()
+	s.ready.Broadcast()
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// cancel the tasks remaining in the queue
+		s.mutex.Lock()
+		s.abandoned = true
+		s.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+type priorityItem struct {
+	task     Task
+	score    int64
+	sequence uint64
+}
+
+// priorityQueue implements heap.Interface with the highest score first and FIFO order for equal scores
+type priorityQueue []*priorityItem
+
+func (q priorityQueue) Len() int {
+	return len(q)
+}
+
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score > q[j].score
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+func (q priorityQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *priorityQueue) Push(x interface{}) {
+	*q = append(*q, x.(*priorityItem))
+}
+
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
@@ -0,0 +1,94 @@
+package task_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+	"github.com/patrickhuber/go-task/tasktest"
+)
+
+var _ = Describe("PriorityScheduler", func() {
+	var (
+		order   []int
+		mutex   sync.Mutex
+		release chan struct{}
+	)
+	BeforeEach(func() {
+		order = []int{}
+		release = make(chan struct{})
+	})
+	record := func(state interface{}) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		order = append(order, state.(int))
+	}
+	// occupy the only worker so queued tasks are ordered by the scheduler
+	block := func(scheduler task.Scheduler) task.Task {
+		started := make(chan struct{})
+		t := task.RunAction(func() {
+			close(started)
+			<-release
+		}, task.WithScheduler(scheduler))
+		<-started
+		return t
+	}
+	It("runs highest priority first", func() {
+		scheduler := task.NewPriorityScheduler(1, task.WithAging(0))
+		defer scheduler.Shutdown(context.Background())
+		blocker := block(scheduler)
+		tasks := []task.Task{blocker}
+		for _, priority := range []int{1, 3, 2, 3} {
+			tasks = append(tasks, task.RunActionWith(record,
+				task.WithState(priority),
+				task.WithPriority(priority),
+				task.WithScheduler(scheduler)))
+		}
+		close(release)
+		Expect(task.WhenAll(tasks...).Wait()).To(BeNil())
+		Expect(order).To(Equal([]int{3, 3, 2, 1}))
+	})
+	It("ages waiting tasks", func() {
+		clock := tasktest.NewClock(time.Now())
+		scheduler := task.NewPriorityScheduler(1,
+			task.WithAging(time.Millisecond),
+			task.WithPrioritySchedulerClock(clock))
+		defer scheduler.Shutdown(context.Background())
+		blocker := block(scheduler)
+		low := task.RunActionWith(record, task.WithState(0), task.WithScheduler(scheduler))
+		clock.AdvanceBy(10 * time.Millisecond)
+		high := task.RunActionWith(record, task.WithState(5), task.WithPriority(5), task.WithScheduler(scheduler))
+		close(release)
+		Expect(task.WhenAll(blocker, low, high).Wait()).To(BeNil())
+		Expect(order).To(Equal([]int{0, 5}))
+	})
+	It("ages by the clock", func() {
+		clock := tasktest.NewClock(time.Now())
+		scheduler := task.NewPriorityScheduler(1,
+			task.WithAging(time.Millisecond),
+			task.WithPrioritySchedulerClock(clock))
+		defer scheduler.Shutdown(context.Background())
+		blocker := block(scheduler)
+		low := task.RunActionWith(record, task.WithState(0), task.WithScheduler(scheduler))
+		clock.AdvanceBy(4 * time.Millisecond)
+		high := task.RunActionWith(record, task.WithState(5), task.WithPriority(5), task.WithScheduler(scheduler))
+		close(release)
+		Expect(task.WhenAll(blocker, low, high).Wait()).To(BeNil())
+		Expect(order).To(Equal([]int{5, 0}))
+	})
+	It("rejects tasks after shutdown", func() {
+		scheduler := task.NewPriorityScheduler(1)
+		Expect(scheduler.Shutdown(context.Background())).To(BeNil())
+		t := task.RunAction(func() {}, task.WithScheduler(scheduler))
+		Expect(t.Wait()).To(Equal(task.ErrSchedulerShutdown))
+	})
+	It("continuation can inherit priority", func() {
+		t := task.NewAction(func() {}, task.WithPriority(3))
+		Expect(t.Priority()).To(Equal(3))
+		Expect(t.ContinueAction(func(task.Task) {}).Priority()).To(Equal(0))
+		Expect(t.ContinueAction(func(task.Task) {}, task.InheritPriority()).Priority()).To(Equal(3))
+	})
+})
@@ -0,0 +1,76 @@
+package task
+
+type Then func(interface{}) (interface{}, error)
+type Catch func(error) (interface{}, error)
+type Finally func()
+type Bind func(interface{}) Task
+
+// Promise chains continuations that propagate the outcome of the antecedent
+type Promise interface {
+	// Then runs the function with the antecedent result if the antecedent completed successfully.
+	// Faults and cancellation of the antecedent are propagated without running the function.
+	Then(Then) Task
+	// Catch runs the function with the antecedent error if the antecedent faulted. The returned
+	// result and error replace the fault. Success and cancellation of the antecedent are propagated.
+	Catch(Catch) Task
+	// Finally runs the function when the antecedent completes, including when it was canceled, and
+	// propagates the antecedent outcome
+	Finally(Finally) Task
+	// Bind runs the function with the antecedent result if the antecedent completed successfully and
+	// returns a proxy that completes when the task returned by the function completes, see Unwrap.
+	// Faults and cancellation of the antecedent are propagated without running the function.
+	Bind(Bind) Task
+}
+
+// canceledError is returned by a delegate to transition its task to canceled with the wrapped error
+type canceledError struct {
+	err error
+}
+
+func (e *canceledError) Error() string {
+	if e.err == nil {
+		return "task canceled"
+	}
+	return e.err.Error()
+}
+
+func (e *canceledError) Unwrap() error {
+	return e.err
+}
+
+// propagate returns the outcome of the completed task as delegate return values
+func propagate(t Task) (interface{}, error) {
+	switch t.Status() {
+	case StatusCanceled:
+		return nil, &canceledError{err: t.Error()}
+	case StatusFaulted:
+		return nil, t.Error()
+	default:
+		return t.Result(), nil
+	}
+}
+
+func (t *task) Then(then Then) Task {
+	return t.ContinueErrFunc(func(antecedent Task) (interface{}, error) {
+		if !antecedent.IsSuccess() {
+			return propagate(antecedent)
+		}
+		return then(antecedent.Result())
+	})
+}
+
+func (t *task) Catch(catch Catch) Task {
+	return t.ContinueErrFunc(func(antecedent Task) (interface{}, error) {
+		if !antecedent.IsFaulted() {
+			return propagate(antecedent)
+		}
+		return catch(antecedent.Error())
+	})
+}
+
+func (t *task) Finally(finally Finally) Task {
+	return t.ContinueErrFunc(func(antecedent Task) (interface{}, error) {
+		finally()
+		return propagate(antecedent)
+	})
+}
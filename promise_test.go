@@ -0,0 +1,149 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("Promise", func() {
+	increment := func(result interface{}) (interface{}, error) {
+		return result.(int) + 1, nil
+	}
+	observe := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	Describe("Then", func() {
+		It("runs on success", func() {
+			t := task.FromResult(1).Then(increment).Then(increment)
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(3))
+		})
+		It("propagates fault", func() {
+			expected := fmt.Errorf("error")
+			count := 0
+			t := task.FromError(expected).Then(func(result interface{}) (interface{}, error) {
+				count++
+				return nil, nil
+			})
+			Expect(t.Wait()).To(Equal(expected))
+			Expect(t.IsFaulted()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+		It("propagates cancellation", func() {
+			source := task.NewCompletionSource()
+			t := source.Task().Then(increment).Then(increment)
+			source.SetCanceled()
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.IsCanceled()).To(BeTrue())
+		})
+		It("propagates cancellation of the antecedent context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			count := 0
+			t := task.RunContextErrAction(observe, task.WithContext(ctx)).Then(func(result interface{}) (interface{}, error) {
+				count++
+				return nil, nil
+			})
+			cancel()
+			err := t.Wait()
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+		It("faults on error", func() {
+			t := task.FromResult(1).Then(func(result interface{}) (interface{}, error) {
+				return nil, fmt.Errorf("error")
+			}).Then(increment)
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.IsFaulted()).To(BeTrue())
+		})
+	})
+	Describe("Catch", func() {
+		It("can recover", func() {
+			t := task.FromError(fmt.Errorf("error")).Then(increment).Catch(func(err error) (interface{}, error) {
+				return 1, nil
+			}).Then(increment)
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(2))
+		})
+		It("can rethrow", func() {
+			expected := fmt.Errorf("rethrow")
+			t := task.FromError(fmt.Errorf("error")).Catch(func(err error) (interface{}, error) {
+				return nil, expected
+			})
+			Expect(t.Wait()).To(Equal(expected))
+		})
+		It("propagates success", func() {
+			count := 0
+			t := task.FromResult(1).Catch(func(err error) (interface{}, error) {
+				count++
+				return nil, nil
+			})
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(1))
+			Expect(count).To(Equal(0))
+		})
+		It("propagates cancellation of the antecedent source", func() {
+			cancellation := task.NewCancellationSource()
+			count := 0
+			t := task.RunContextErrAction(observe, task.WithCancellation(cancellation)).Catch(func(err error) (interface{}, error) {
+				count++
+				return nil, nil
+			})
+			cancellation.Cancel()
+			err := t.Wait()
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(0))
+		})
+	})
+	Describe("Finally", func() {
+		It("runs on success", func() {
+			count := 0
+			t := task.FromResult(1).Finally(func() { count++ })
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(1))
+			Expect(count).To(Equal(1))
+		})
+		It("runs on fault", func() {
+			count := 0
+			t := task.FromError(fmt.Errorf("error")).Finally(func() { count++ })
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.IsFaulted()).To(BeTrue())
+			Expect(count).To(Equal(1))
+		})
+		It("runs on cancel", func() {
+			count := 0
+			source := task.NewCompletionSource()
+			t := source.Task().Finally(func() { count++ })
+			source.SetCanceled()
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(1))
+		})
+		It("runs when the antecedent context is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			count := 0
+			t := task.RunContextErrAction(observe, task.WithContext(ctx)).Finally(func() { count++ })
+			cancel()
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(1))
+		})
+		It("runs when the antecedent source is canceled", func() {
+			cancellation := task.NewCancellationSource()
+			count := 0
+			source := task.NewCompletionSource(task.WithCancellation(cancellation))
+			t := source.Task().Finally(func() { count++ })
+			cancellation.Cancel()
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.IsCanceled()).To(BeTrue())
+			Expect(count).To(Equal(1))
+		})
+	})
+})
@@ -40,11 +40,6 @@ func (s *queueScheduler) Dequeue() bool {
 	go func(t Task) {
 		t.Start()
 	}(t)
-
-	// execute the blocking call of the task
-	go func(t Task) {
-		t.Wait()
-	}(t)
 	return true
 }
 
@@ -2,6 +2,7 @@ package task
 
 import (
 	"io"
+	"sync"
 
 	"github.com/patrickhuber/go-collections/list"
 	concurrent_list "github.com/patrickhuber/go-collections/concurrent/list"
@@ -58,6 +59,29 @@ func (o *observer) OnError(err error) {
 	}
 }
 
+type statusObserver struct {
+	observer
+	onStatus func(TaskStatus)
+}
+
+// NewStatusObserver creates an observer that is notified of the non terminal status transitions of a task
+func NewStatusObserver(
+	onStatus func(TaskStatus),
+	onCompleted func()) StatusObserver {
+	return &statusObserver{
+		observer: observer{
+			onCompleted: onCompleted,
+		},
+		onStatus: onStatus,
+	}
+}
+
+func (o *statusObserver) OnStatus(status TaskStatus) {
+	if o.onStatus != nil {
+		o.onStatus(status)
+	}
+}
+
 type Observable interface {
 	Subscribe(Observer) io.Closer
 	Unsubscribe(Observer)
@@ -82,6 +106,28 @@ func (s *subscription) Close() error {
 
 type tracker struct {
 	observers list.List
+	// terminal is the terminal notification replayed to observers that subscribe after completion
+	terminal *terminalNotification
+	mutex    sync.Mutex
+}
+
+type terminalNotification struct {
+	status TaskStatus
+	result interface{}
+	err    error
+}
+
+// send notifies the observer of the terminal status followed by OnCompleted
+func (n *terminalNotification) send(o Observer) {
+	switch n.status {
+	case StatusSuccess:
+		o.OnNext(n.result)
+	case StatusFaulted:
+		o.OnError(n.err)
+	case StatusCanceled:
+		o.OnCanceled(n.err)
+	}
+	o.OnCompleted()
 }
 
 type Tracker interface {
@@ -91,6 +137,12 @@ type Tracker interface {
 	NotifyNext(interface{})
 	NotifyCompleted()
 	NotifyCanceled(error)
+	// NotifyStatus notifies the observers that implement StatusObserver of a status transition
+	NotifyStatus(TaskStatus)
+	// NotifyTerminal notifies the observers of the terminal status with OnNext, OnError or OnCanceled
+	// followed by OnCompleted and closes the tracker. Observers that subscribe afterwards receive the
+	// same notifications when they subscribe. Only the first terminal notification is sent.
+	NotifyTerminal(status TaskStatus, result interface{}, err error)
 }
 
 func NewTracker() Tracker {
@@ -100,9 +152,17 @@ func NewTracker() Tracker {
 }
 
 func (t *tracker) Subscribe(observer Observer) io.Closer {
-	if !t.observers.Contains(observer) {
+	t.mutex.Lock()
+	terminal := t.terminal
+	if terminal == nil && !t.observers.Contains(observer) {
 		t.observers.Append(observer)
 	}
+	t.mutex.Unlock()
+
+	// the tracker is complete, replay the terminal notification
+	if terminal != nil {
+		terminal.send(observer)
+	}
 	return NewSubscription(observer, t)
 }
 
@@ -137,6 +197,33 @@ func (t *tracker) NotifyCanceled(err error) {
 	})
 }
 
+func (t *tracker) NotifyTerminal(status TaskStatus, result interface{}, err error) {
+	t.mutex.Lock()
+	if t.terminal != nil {
+		t.mutex.Unlock()
+		return
+	}
+	terminal := &terminalNotification{
+		status: status,
+		result: result,
+		err:    err,
+	}
+	t.terminal = terminal
+	t.mutex.Unlock()
+
+	// observers subscribed from here on are replayed the notification instead
+	t.notify(terminal.send)
+	t.Close()
+}
+
+func (t *tracker) NotifyStatus(status TaskStatus) {
+	t.notify(func(o Observer) {
+		if s, ok := o.(StatusObserver); ok {
+			s.OnStatus(status)
+		}
+	})
+}
+
 func (t *tracker) notify(action func(o Observer)) {
 	t.observers.ForEach(func(item interface{}) {
 		if o, exists := item.(Observer); exists {
@@ -2,6 +2,8 @@ package task_test
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -79,4 +81,44 @@ var _ = Describe("Subscriber", func() {
 		tracker.NotifyCompleted()
 		Expect(observer.completedCount).To(Equal(1))
 	})
+	It("can publish terminal", func() {
+		tracker.Subscribe(observer)
+		tracker.NotifyTerminal(task.StatusSuccess, 1, nil)
+		Expect(observer.nextCount).To(Equal(1))
+		Expect(observer.completedCount).To(Equal(1))
+	})
+	It("replays terminal to late subscribers", func() {
+		tracker.NotifyTerminal(task.StatusFaulted, nil, fmt.Errorf("error"))
+		tracker.Subscribe(observer)
+		Expect(observer.errorCount).To(Equal(1))
+		Expect(observer.completedCount).To(Equal(1))
+	})
+	It("publishes terminal once", func() {
+		tracker.Subscribe(observer)
+		tracker.NotifyTerminal(task.StatusCanceled, nil, fmt.Errorf("error"))
+		tracker.NotifyTerminal(task.StatusSuccess, 1, nil)
+		Expect(observer.canceledCount).To(Equal(1))
+		Expect(observer.nextCount).To(Equal(0))
+		Expect(observer.completedCount).To(Equal(1))
+	})
+	It("notifies each subscriber once when completing concurrently", func() {
+		for i := 0; i < 100; i++ {
+			tracker := task.NewTracker()
+			var completed int32
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				tracker.NotifyTerminal(task.StatusSuccess, 1, nil)
+			}()
+			go func() {
+				defer wg.Done()
+				tracker.Subscribe(task.NewObserver(nil, func() {
+					atomic.AddInt32(&completed, 1)
+				}, nil, nil))
+			}()
+			wg.Wait()
+			Expect(atomic.LoadInt32(&completed)).To(Equal(int32(1)))
+		}
+	})
 })
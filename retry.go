@@ -0,0 +1,161 @@
+package task
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// Backoff returns the delay before the next attempt. Attempt is the number of the attempt that
+// failed starting at 1 and previous is the delay returned for the previous attempt.
+type Backoff func(attempt int, previous time.Duration) time.Duration
+
+// RetryPolicy configures how a task retries its delegate, see WithRetry
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the delegate is invoked. Values less than one invoke the delegate once.
+	MaxAttempts int
+	// Backoff returns the delay between attempts. A nil Backoff retries immediately.
+	Backoff Backoff
+	// ShouldRetry returns true if the error can be retried. A nil ShouldRetry retries all errors.
+	ShouldRetry func(error) bool
+	// AttemptTimeout cancels the context of an attempt after the timeout. Zero disables the timeout.
+	AttemptTimeout time.Duration
+}
+
+// WithRetry invokes the delegate again when it returns an error until the policy is exhausted.
+// Cancellation of the task is observed between attempts, the backoff waits on the task clock without
+// blocking a worker. When all attempts fail the task faults with an AggregateError of the attempt errors.
+func WithRetry(policy RetryPolicy) RunOption {
+	return func(t *task) {
+		t.retry = &policy
+	}
+}
+
+// ConstantBackoff waits the same delay between attempts
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration {
+		return delay
+	}
+}
+
+// LinearBackoff waits initial after the first attempt and increases the delay by increment after each attempt
+func LinearBackoff(initial time.Duration, increment time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		return initial + time.Duration(attempt-1)*increment
+	}
+}
+
+// ExponentialBackoff doubles the delay after each attempt starting at initial, the delay never exceeds max
+func ExponentialBackoff(initial time.Duration, max time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		delay := initial
+		for i := 1; i < attempt; i++ {
+			delay *= 2
+			if delay >= max || delay <= 0 {
+				return max
+			}
+		}
+		if delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+// DecorrelatedJitterBackoff picks a random delay between base and three times the previous delay,
+// the delay never exceeds max. The randomness spreads out retries of concurrent tasks.
+func DecorrelatedJitterBackoff(base time.Duration, max time.Duration) Backoff {
+	return func(_ int, previous time.Duration) time.Duration {
+		if previous < base {
+			previous = base
+		}
+		upper := previous * 3
+		delay := base
+		if upper > base {
+			delay += time.Duration(rand.Int63n(int64(upper - base)))
+		}
+		if delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+// invokeWithRetry invokes the delegate until it succeeds or the retry policy is exhausted. The backoff
+// does not block the routine, the next attempt is queued on the task scheduler when the clock timer
+// fires. Attempt is the number of the attempt, errs and delay are carried over from the previous attempts.
+func (t *task) invokeWithRetry(attempt int, errs []error, delay time.Duration) {
+	policy := t.retry
+	result, err := t.attempt(policy)
+	if err == nil {
+		t.returned(result, nil)
+		return
+	}
+	errs = append(errs, err)
+
+	// stop retrying if the task was canceled, the attempt error is the context error
+	if ctxErr := t.context.Err(); ctxErr != nil {
+		t.returned(nil, ctxErr)
+		return
+	}
+	if attempt >= policy.MaxAttempts {
+		t.returned(nil, newAggregateError(errs...))
+		return
+	}
+	if policy.ShouldRetry != nil && !policy.ShouldRetry(err) {
+		t.returned(nil, newAggregateError(errs...))
+		return
+	}
+
+	if policy.Backoff != nil {
+		delay = policy.Backoff(attempt, delay)
+	}
+	if delay <= 0 {
+		t.invokeWithRetry(attempt+1, errs, delay)
+		return
+	}
+	t.wait(delay, func() {
+		t.invokeWithRetry(attempt+1, errs, delay)
+	})
+}
+
+// attempt invokes the delegate once with the attempt timeout of the policy
+func (t *task) attempt(policy *RetryPolicy) (interface{}, error) {
+	if policy.AttemptTimeout <= 0 {
+		return t.invoke(t.context)
+	}
+	ctx, cancel := withClockTimeout(t.context, t.clock, policy.AttemptTimeout)
+	defer cancel()
+	return t.invoke(ctx)
+}
+
+// wait queues the next attempt on the task scheduler after the delay of the task clock. While waiting
+// the task is in backoff and cancellation completes it, the next attempt is then skipped.
+func (t *task) wait(delay time.Duration, next func()) {
+	// the worker runs the next attempt on the task scheduler like a queued task
+	worker := new(func(context.Context, interface{}) (interface{}, error) {
+		t.mutex.Lock()
+		if atomic.LoadInt32(&t.terminal) != 0 {
+			t.mutex.Unlock()
+			return nil, nil
+		}
+		// the context is done before the watcher cancels the task, do not start another attempt
+		if err := t.context.Err(); err != nil {
+			t.mutex.Unlock()
+			t.cancelPending(err)
+			return nil, nil
+		}
+		t.backoff = false
+		t.mutex.Unlock()
+		next()
+		return nil, nil
+	})
+	worker.apply(WithScheduler(t.scheduler), WithClock(t.clock))
+
+	t.mutex.Lock()
+	t.backoff = true
+	timer := t.clock.AfterFunc(delay, worker.queue)
+	t.addCancel(func() { timer.Stop() })
+	t.mutex.Unlock()
+}
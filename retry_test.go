@@ -0,0 +1,175 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+	"github.com/patrickhuber/go-task/tasktest"
+)
+
+var _ = Describe("Retry", func() {
+	It("retries until success", func() {
+		count := 0
+		t := task.RunErrFunc(func() (interface{}, error) {
+			count++
+			if count < 3 {
+				return nil, fmt.Errorf("error %d", count)
+			}
+			return count, nil
+		}, task.WithRetry(task.RetryPolicy{MaxAttempts: 5}))
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(3))
+		Expect(t.Attempts()).To(Equal(3))
+	})
+	It("faults with all attempt errors", func() {
+		t := task.RunErrAction(func() error {
+			return fmt.Errorf("error")
+		}, task.WithRetry(task.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     task.ConstantBackoff(time.Millisecond),
+		}))
+		err := t.Wait()
+		Expect(err).ToNot(BeNil())
+		Expect(t.IsFaulted()).To(BeTrue())
+		Expect(t.Attempts()).To(Equal(3))
+		aggregate, ok := err.(task.AggregateError)
+		Expect(ok).To(BeTrue())
+		Expect(len(aggregate.Errors())).To(Equal(3))
+	})
+	It("does not modify an aggregate returned by an attempt", func() {
+		when := task.WhenAll(task.FromError(fmt.Errorf("one")), task.FromError(fmt.Errorf("two")))
+		t := task.RunErrAction(func() error {
+			return when.Wait()
+		}, task.WithRetry(task.RetryPolicy{MaxAttempts: 2}))
+		err := t.Wait()
+		Expect(err.(task.AggregateError).Errors()).To(HaveLen(2))
+		Expect(when.Error().(task.AggregateError).Errors()).To(HaveLen(2))
+	})
+	It("stops when the error is not retryable", func() {
+		permanent := errors.New("permanent")
+		t := task.RunErrAction(func() error {
+			return permanent
+		}, task.WithRetry(task.RetryPolicy{
+			MaxAttempts: 3,
+			ShouldRetry: func(err error) bool {
+				return !errors.Is(err, permanent)
+			},
+		}))
+		Expect(t.Wait()).ToNot(BeNil())
+		Expect(t.Attempts()).To(Equal(1))
+	})
+	It("times out each attempt", func() {
+		t := task.RunContextErrAction(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, task.WithRetry(task.RetryPolicy{
+			MaxAttempts:    2,
+			AttemptTimeout: time.Millisecond,
+		}))
+		err := t.Wait()
+		Expect(t.IsFaulted()).To(BeTrue())
+		Expect(t.Attempts()).To(Equal(2))
+		Expect(err.(task.AggregateError).Errors()[0]).To(Equal(context.DeadlineExceeded))
+	})
+	It("observes cancellation between attempts", func() {
+		source := task.NewCancellationSource()
+		failed := make(chan struct{})
+		t := task.RunErrAction(func() error {
+			close(failed)
+			return fmt.Errorf("error")
+		}, task.WithCancellation(source), task.WithRetry(task.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     task.ConstantBackoff(time.Hour),
+		}))
+		<-failed
+		source.Cancel()
+		Expect(t.Wait()).To(Equal(context.Canceled))
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(t.Attempts()).To(Equal(1))
+	})
+	It("observes context cancellation between attempts", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		failed := make(chan struct{})
+		t := task.RunErrAction(func() error {
+			close(failed)
+			return fmt.Errorf("error")
+		}, task.WithContext(ctx), task.WithRetry(task.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     task.ConstantBackoff(time.Hour),
+		}))
+		<-failed
+		cancel()
+		Expect(t.Wait()).To(Equal(context.Canceled))
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(t.Attempts()).To(Equal(1))
+	})
+	It("waits for the backoff on the virtual scheduler", func() {
+		scheduler := tasktest.NewVirtualScheduler(time.Now())
+		t := task.RunErrAction(func() error {
+			return fmt.Errorf("error")
+		}, task.WithScheduler(scheduler), task.WithRetry(task.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     task.ConstantBackoff(time.Second),
+		}))
+		scheduler.RunUntilIdle()
+		Expect(t.Attempts()).To(Equal(1))
+		Expect(t.IsCompleted()).To(BeFalse())
+		scheduler.AdvanceBy(time.Second)
+		Expect(t.Attempts()).To(Equal(2))
+		Expect(t.IsCompleted()).To(BeFalse())
+		scheduler.AdvanceBy(time.Second)
+		Expect(t.Attempts()).To(Equal(3))
+		Expect(t.IsFaulted()).To(BeTrue())
+	})
+	It("does not start an attempt after the context is done on the virtual scheduler", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		scheduler := tasktest.NewVirtualScheduler(time.Now())
+		t := task.RunErrAction(func() error {
+			return fmt.Errorf("error")
+		}, task.WithContext(ctx), task.WithScheduler(scheduler), task.WithRetry(task.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     task.ConstantBackoff(time.Second),
+		}))
+		scheduler.RunUntilIdle()
+		Expect(t.Attempts()).To(Equal(1))
+		cancel()
+		scheduler.AdvanceBy(time.Second)
+		Expect(t.Attempts()).To(Equal(1))
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(t.Error()).To(Equal(context.Canceled))
+	})
+	Describe("Backoff", func() {
+		It("is constant", func() {
+			backoff := task.ConstantBackoff(time.Second)
+			Expect(backoff(1, 0)).To(Equal(time.Second))
+			Expect(backoff(5, time.Second)).To(Equal(time.Second))
+		})
+		It("is linear", func() {
+			backoff := task.LinearBackoff(time.Second, 2*time.Second)
+			Expect(backoff(1, 0)).To(Equal(time.Second))
+			Expect(backoff(3, 0)).To(Equal(5 * time.Second))
+		})
+		It("is exponential", func() {
+			backoff := task.ExponentialBackoff(time.Second, 10*time.Second)
+			Expect(backoff(1, 0)).To(Equal(time.Second))
+			Expect(backoff(3, 0)).To(Equal(4 * time.Second))
+			Expect(backoff(5, 0)).To(Equal(10 * time.Second))
+			Expect(backoff(100, 0)).To(Equal(10 * time.Second))
+		})
+		It("is decorrelated jitter", func() {
+			backoff := task.DecorrelatedJitterBackoff(time.Second, 10*time.Second)
+			previous := time.Duration(0)
+			for attempt := 1; attempt < 20; attempt++ {
+				delay := backoff(attempt, previous)
+				Expect(delay).To(BeNumerically(">=", time.Second))
+				Expect(delay).To(BeNumerically("<=", 10*time.Second))
+				previous = delay
+			}
+		})
+	})
+})
@@ -1,5 +1,7 @@
 package task
 
+import "context"
+
 // RunAction runs the given action function with the supplied RunOptions
 // An Action is a function with no arguments and no returns
 func RunAction(action Action, options ...RunOption) Task {
@@ -57,13 +59,26 @@ func RunErrFunc(f ErrFunc, options ...RunOption) Task {
 }
 
 func RunErrFuncWith(errFuncWith ErrFuncWith, options ...RunOption) Task {
+	return RunContextErrFuncWith(ignoreContext(errFuncWith), options...)
+}
+
+// RunContextErrAction runs the given context aware action with the supplied RunOptions
+// The context is canceled when the task is canceled, the action should observe it and return early.
+func RunContextErrAction(contextErrAction ContextErrAction, options ...RunOption) Task {
+	delegate := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return nil, contextErrAction(ctx)
+	}
+	return RunContextErrFuncWith(delegate, options...)
+}
+
+// RunContextErrFuncWith runs the given context aware function with the supplied RunOptions
+// The context is canceled when the task is canceled, the function should observe it and return early.
+func RunContextErrFuncWith(contextErrFuncWith ContextErrFuncWith, options ...RunOption) Task {
 	// create the task
-	t := new(errFuncWith)
+	t := new(contextErrFuncWith)
 
 	// apply operations
-	for _, opt := range options {
-		opt(t)
-	}
-	t.scheduler.Queue(t)
+	t.apply(options...)
+	t.queue()
 	return t
 }
@@ -23,7 +23,4 @@ func (s *scheduler) Queue(t Task) {
 	go func(t Task) {
 		t.Start()
 	}(t)
-	go func(t Task) {
-		t.Wait()
-	}(t)
 }
@@ -0,0 +1,105 @@
+package task_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+	"github.com/patrickhuber/go-task/tasktest"
+)
+
+var _ = Describe("Status", func() {
+	var (
+		scheduler tasktest.VirtualScheduler
+		start     time.Time
+	)
+	BeforeEach(func() {
+		start = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		scheduler = tasktest.NewVirtualScheduler(start)
+	})
+	It("is created before start", func() {
+		t := task.NewAction(func() {})
+		Expect(t.Status()).To(Equal(task.StatusCreated))
+	})
+	It("is waiting to run when queued", func() {
+		t := task.RunAction(func() {}, task.WithScheduler(scheduler))
+		Expect(t.Status()).To(Equal(task.StatusWaitingToRun))
+		scheduler.RunUntilIdle()
+		Expect(t.Status()).To(Equal(task.StatusSuccess))
+	})
+	It("is running in the delegate", func() {
+		var status task.TaskStatus
+		var t task.Task
+		t = task.NewAction(func() {
+			status = t.Status()
+		})
+		t.Start()
+		Expect(t.Wait()).To(BeNil())
+		Expect(status).To(Equal(task.StatusRunning))
+	})
+	It("is waiting for children", func() {
+		release := make(chan struct{})
+		parent := task.RunContextErrAction(func(ctx context.Context) error {
+			task.RunAction(func() {
+				<-release
+			}, task.WithContext(ctx), task.AttachedToParent())
+			return nil
+		})
+		Eventually(parent.Status).Should(Equal(task.StatusWaitingForChildren))
+		close(release)
+		Expect(parent.Wait()).To(BeNil())
+		Expect(parent.Status()).To(Equal(task.StatusSuccess))
+	})
+	It("does not leave a terminal status", func() {
+		t := task.NewAction(func() {})
+		t.Cancel()
+		t.Start()
+		Expect(t.Status()).To(Equal(task.StatusCanceled))
+		Expect(t.Timestamps().Started.IsZero()).To(BeTrue())
+	})
+	It("notifies status observers", func() {
+		var mutex sync.Mutex
+		statuses := []task.TaskStatus{}
+		t := task.NewAction(func() {}, task.WithScheduler(scheduler))
+		t.Subscribe(task.NewStatusObserver(func(status task.TaskStatus) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			statuses = append(statuses, status)
+		}, nil))
+		scheduler.Queue(t)
+		t.Start()
+		Expect(t.Wait()).To(BeNil())
+		mutex.Lock()
+		defer mutex.Unlock()
+		Expect(statuses).To(Equal([]task.TaskStatus{task.StatusRunning}))
+	})
+	It("notifies status observers of queued tasks", func() {
+		statuses := []task.TaskStatus{}
+		antecedent := task.NewAction(func() {}, task.WithScheduler(scheduler))
+		continuation := antecedent.ContinueAction(func(task.Task) {})
+		continuation.Subscribe(task.NewStatusObserver(func(status task.TaskStatus) {
+			statuses = append(statuses, status)
+		}, nil))
+		antecedent.Start()
+		scheduler.RunUntilIdle()
+		Expect(continuation.IsSuccess()).To(BeTrue())
+		Expect(statuses).To(Equal([]task.TaskStatus{task.StatusWaitingToRun, task.StatusRunning}))
+	})
+	It("records timestamps", func() {
+		antecedent := task.NewAction(func() {}, task.WithScheduler(scheduler))
+		t := antecedent.ContinueAction(func(task.Task) {})
+		Expect(t.Timestamps().Queued.IsZero()).To(BeTrue())
+		scheduler.AdvanceBy(time.Second)
+		antecedent.Start()
+		scheduler.AdvanceBy(time.Second)
+		scheduler.RunUntilIdle()
+		timestamps := t.Timestamps()
+		Expect(timestamps.Created).To(Equal(start))
+		Expect(timestamps.Queued).To(Equal(start.Add(time.Second)))
+		Expect(timestamps.Started).ToNot(BeTemporally("<", timestamps.Queued))
+		Expect(timestamps.Finished).ToNot(BeTemporally("<", timestamps.Started))
+	})
+})
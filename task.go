@@ -2,25 +2,58 @@ package task
 
 import (
 	"context"
+	"errors"
 	"io"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type TaskStatus string
 
 const (
-	StatusCreated  TaskStatus = "created"
-	StatusSuccess  TaskStatus = "success"
-	StatusFaulted  TaskStatus = "faulted"
-	StatusCanceled TaskStatus = "canceled"
+	StatusCreated TaskStatus = "created"
+	// StatusWaitingToRun is the status of a task queued on a scheduler
+	StatusWaitingToRun TaskStatus = "waiting_to_run"
+	// StatusRunning is the status of a task whose delegate is running
+	StatusRunning TaskStatus = "running"
+	// StatusWaitingForChildren is the status of a task whose delegate returned while attached children are running
+	StatusWaitingForChildren TaskStatus = "waiting_for_children"
+	StatusSuccess            TaskStatus = "success"
+	StatusFaulted            TaskStatus = "faulted"
+	StatusCanceled           TaskStatus = "canceled"
 )
 
+// transitions lists the statuses a task can move to from each non terminal status
+var transitions = map[TaskStatus][]TaskStatus{
+	StatusCreated:            {StatusWaitingToRun, StatusRunning, StatusSuccess, StatusFaulted, StatusCanceled},
+	StatusWaitingToRun:       {StatusRunning, StatusSuccess, StatusFaulted, StatusCanceled},
+	StatusRunning:            {StatusWaitingForChildren, StatusSuccess, StatusFaulted, StatusCanceled},
+	StatusWaitingForChildren: {StatusSuccess, StatusFaulted, StatusCanceled},
+}
+
+// Timestamps records when a task reached each stage of its lifecycle. Stages the task has not
+// reached are the zero time.
+type Timestamps struct {
+	Created  time.Time
+	Queued   time.Time
+	Started  time.Time
+	Finished time.Time
+}
+
+// StatusObserver is an Observer that is also notified of non terminal status transitions
+type StatusObserver interface {
+	Observer
+	OnStatus(TaskStatus)
+}
+
 // Task represents a unit of work.
 type Task interface {
 	// Start executes the task. This is called by the scheduler to start the task.
 	Start()
 	// Wait will return immediately if the task is complete. It will block if the task is running.
+	// When the task context is done Wait cancels the task and returns the context error.
 	Wait() error
 	// Result returns the result. It will not block and will return immediately.
 	Result() interface{}
@@ -36,8 +69,18 @@ type Task interface {
 	IsSuccess() bool
 	// Status returns the task status
 	Status() TaskStatus
+	// Priority returns the priority set with WithPriority, higher values run first on a PriorityScheduler
+	Priority() int
+	// Attempts returns the number of times the delegate was invoked, see WithRetry
+	Attempts() int
+	// Cancel cancels the task context. A task that has not started transitions to the canceled status,
+	// a running delegate observes the context and completes the task when it returns.
+	Cancel()
+	// Timestamps returns the times of the status transitions of the task
+	Timestamps() Timestamps
 
 	Continuation
+	Promise
 	Observer
 	Observable
 }
@@ -50,55 +93,113 @@ type ContinueFunc func(Task) interface{}
 type ContinueFuncWith func(Task, interface{}) interface{}
 type ContinueErrFunc func(Task) (interface{}, error)
 type ContinueErrFuncWith func(Task, interface{}) (interface{}, error)
+type ContinueContextErrAction func(context.Context, Task) error
+type ContinueContextErrFuncWith func(context.Context, Task, interface{}) (interface{}, error)
+type ContinueWithTask func(Task) Task
 
 type Continuation interface {
-	ContinueAction(ContinueAction) Task
-	ContinueActionWith(ContinueActionWith) Task
-	ContinueErrActionWith(ContinueErrActionWith) Task
-	ContinueErrAction(ContinueErrAction) Task
-	ContinueFunc(ContinueFunc) Task
-	ContinueFuncWith(ContinueFuncWith) Task
-	ContinueErrFunc(ContinueErrFunc) Task
-	ContinueErrFuncWith(ContinueErrFuncWith) Task
+	ContinueAction(ContinueAction, ...ContinuationOption) Task
+	ContinueActionWith(ContinueActionWith, ...ContinuationOption) Task
+	ContinueErrActionWith(ContinueErrActionWith, ...ContinuationOption) Task
+	ContinueErrAction(ContinueErrAction, ...ContinuationOption) Task
+	ContinueFunc(ContinueFunc, ...ContinuationOption) Task
+	ContinueFuncWith(ContinueFuncWith, ...ContinuationOption) Task
+	ContinueErrFunc(ContinueErrFunc, ...ContinuationOption) Task
+	ContinueErrFuncWith(ContinueErrFuncWith, ...ContinuationOption) Task
+	ContinueContextErrAction(ContinueContextErrAction, ...ContinuationOption) Task
+	ContinueContextErrFuncWith(ContinueContextErrFuncWith, ...ContinuationOption) Task
+	// ContinueWithTask runs a continuation that starts another task and returns a proxy that
+	// completes when that task completes, see Unwrap
+	ContinueWithTask(ContinueWithTask, ...ContinuationOption) Task
 }
 
 type task struct {
 	executeOnce sync.Once
 	status      TaskStatus
-	result      interface{}
-	err         error
-	doneCh      chan struct{}
-	context     context.Context
-	scheduler   Scheduler
-	errFuncWith ErrFuncWith
-	state       interface{}
-	tracker     Tracker
-	mutex       sync.RWMutex // currently this is a shared mutex for all state, switch to individual?
-}
-
-func new(errFuncWith ErrFuncWith) *task {
+	// terminal is set by the single transition to a terminal status
+	terminal int32
+	result   interface{}
+	err      error
+	doneCh   chan struct{}
+	context  context.Context
+	cancel   context.CancelFunc
+	// cancelContext cancels the context of the task without completing the task, see Cancel
+	cancelContext context.CancelFunc
+	// antecedent and continuationOptions are set when this task is a continuation
+	antecedent          Task
+	continuationOptions *continuationOptions
+	// cancellation is the cancellation source observed by this task
+	cancellation CancellationSource
+	scheduler    Scheduler
+	delegate     ContextErrFuncWith
+	state        interface{}
+	priority     int
+	timeout      *time.Duration
+	retry        *RetryPolicy
+	attempts     int32
+	// backoff is set while a retried task waits for its next attempt, no delegate is running so the
+	// task can be canceled like a task that has not started
+	backoff bool
+	clock   Clock
+	// propagatePanics disables the recovery of panics in the delegate
+	propagatePanics bool
+	// attachToParent attaches the task to the parent found in the context, see AttachedToParent
+	attachToParent bool
+	// children is the number of attached children that have not completed
+	children    int
+	childErrors []error
+	// delegateDone is set when the delegate returns, children can no longer attach after that
+	delegateDone bool
+	// pendingTransition completes the task after the last attached child completes
+	pendingTransition func()
+	timestamps        Timestamps
+	tracker           Tracker
+	mutex             sync.RWMutex // currently this is a shared mutex for all state, switch to individual?
+}
+
+func new(delegate ContextErrFuncWith) *task {
 	return &task{
-		context:     context.TODO(),
-		status:      StatusCreated,
-		scheduler:   DefaultScheduler(),
-		errFuncWith: errFuncWith,
-		tracker:     NewTracker(),
+		context:   context.TODO(),
+		status:    StatusCreated,
+		scheduler: DefaultScheduler(),
+		delegate:  delegate,
+		tracker:   NewTracker(),
 		// make this buffered to avoid blocking the calling routine
 		doneCh: make(chan struct{}, 1),
 	}
 }
 
+// ignoreContext adapts a delegate that does not observe cancellation to a context aware delegate
+func ignoreContext(errFuncWith ErrFuncWith) ContextErrFuncWith {
+	return func(_ context.Context, state interface{}) (interface{}, error) {
+		return errFuncWith(state)
+	}
+}
+
 type RunOption func(t *task)
 
+// WithContext sets the context of the task, the delegate receives a context derived from it.
+// Continuations of the task do not inherit the context, use CancelOn to cancel a continuation.
 func WithContext(ctx context.Context) RunOption {
 	return func(t *task) {
 		t.context = ctx
 	}
 }
 
+// WithTimeout cancels the task and its context after the timeout elapses, even if the delegate is
+// still running. A running delegate should observe the context, its result is dropped. The timeout is
+// released when the task completes and is not inherited by continuations.
 func WithTimeout(timeout time.Duration) RunOption {
 	return func(t *task) {
-		t.context, _ = context.WithTimeout(t.context, timeout)
+		t.timeout = &timeout
+	}
+}
+
+// WithClock sets the clock used for the timeout of the task. By default the clock of the scheduler
+// is used if the scheduler provides one, otherwise the DefaultClock.
+func WithClock(clock Clock) RunOption {
+	return func(t *task) {
+		t.clock = clock
 	}
 }
 
@@ -114,6 +215,118 @@ func WithState(state interface{}) RunOption {
 	}
 }
 
+// WithPriority sets the priority of the task. Higher values run first on a PriorityScheduler.
+func WithPriority(priority int) RunOption {
+	return func(t *task) {
+		t.priority = priority
+	}
+}
+
+// WithPanicRecovery enables or disables the recovery of panics in the task delegate. Recovery is
+// enabled by default and faults the task with a PanicError. When disabled a panic crashes the process.
+func WithPanicRecovery(enabled bool) RunOption {
+	return func(t *task) {
+		t.propagatePanics = !enabled
+	}
+}
+
+// WithCancellation cancels the task when the cancellation source is canceled. Continuations of the
+// task are not canceled by the source, use CancelOn to cancel a continuation.
+func WithCancellation(source CancellationSource) RunOption {
+	return func(t *task) {
+		t.context = source.Context()
+		t.cancellation = source
+	}
+}
+
+// apply applies the run options and derives the cancelable context of the task
+func (t *task) apply(options ...RunOption) {
+	for _, opt := range options {
+		opt(t)
+	}
+	if t.clock == nil {
+		t.clock = DefaultClock()
+		if provider, ok := t.scheduler.(clockProvider); ok {
+			t.clock = provider.Clock()
+		}
+	}
+	t.timestamps.Created = t.clock.Now()
+
+	parent := t.context
+	ctx, cancel := context.WithCancel(t.context)
+	t.context = ctx
+	t.cancelContext = cancel
+	t.addCancel(cancel)
+
+	if t.timeout != nil {
+		ctx, cancel := withClockTimeout(t.context, t.clock, *t.timeout)
+		t.context = ctx
+		t.addCancel(cancel)
+
+		// the task is canceled by the clock when the timeout elapses, the result of a delegate that
+		// is still running is dropped
+		timer := t.clock.AfterFunc(*t.timeout, func() {
+			t.setCanceled(context.DeadlineExceeded)
+		})
+		t.addCancel(func() {
+			timer.Stop()
+		})
+	}
+
+	// the registration is released when the task completes
+	if t.cancellation != nil {
+		registration := t.cancellation.Register(t.cancellationRequested)
+		t.addCancel(func() {
+			registration.Close()
+		})
+	}
+
+	if t.attachToParent {
+		t.attachToCurrent()
+	}
+
+	// a task that has not started is canceled when the context is done, even if nobody calls Wait.
+	// the context of a cancellation source is observed through the registration above
+	if t.cancellation == nil || parent != t.cancellation.Context() {
+		registration := registerContext(parent, func() {
+			t.cancelPending(parent.Err())
+		})
+		t.mutex.Lock()
+		t.addCancel(func() {
+			registration.Close()
+		})
+		t.mutex.Unlock()
+	}
+}
+
+// cancelPending transitions the task to canceled if its delegate has not started. A running delegate
+// observes the cancellation through its context and completes the task when it returns.
+// It returns false if the task is running or already complete.
+func (t *task) cancelPending(err error) bool {
+	// lazy continuations are canceled when the antecedent completes and the continuation is scheduled
+	if t.isLazyCancellation() {
+		return false
+	}
+	if !t.finish(true, StatusCanceled, nil, err) {
+		return false
+	}
+	t.tracker.NotifyTerminal(StatusCanceled, nil, err)
+	return true
+}
+
+// addCancel chains the cancel func with any existing cancel func of the task
+func (t *task) addCancel(cancel context.CancelFunc) {
+	previous := t.cancel
+	if previous == nil {
+		t.cancel = cancel
+		return
+	}
+	t.cancel = func() {
+		cancel()
+		previous()
+	}
+}
+
 func (t *task) Start() {
 	t.executeOnce.Do(t.execute)
 }
@@ -124,22 +337,160 @@ func (t *task) execute() {
 		return
 	}
 
-	// cleanup the channel after execution completes, this will activate any select statements
-	defer close(t.doneCh)
+	// tasks without a delegate are completed externally, see CompletionSource
+	if t.delegate == nil {
+		return
+	}
+
+	// do not run the delegate if the task was canceled before it started
+	if err := t.context.Err(); err != nil {
+		t.cancelPending(err)
+		return
+	}
+	if !t.transitionTo(StatusRunning) {
+		return
+	}
 
 	// execute the delegate
-	result, err := t.errFuncWith(t.state)
+	if t.retry != nil {
+		t.invokeWithRetry(1, nil, 0)
+		return
+	}
+	result, err := t.invoke(t.context)
+	t.returned(result, err)
+}
+
+// returned completes the task with the outcome of the delegate once the attached children complete
+func (t *task) returned(result interface{}, err error) {
+	t.completeWithChildren(func() {
+		t.transition(result, err)
+	})
+}
 
-	// notify subscribers
-	if err != nil {
-		t.setError(err)
-		t.setStatus(StatusFaulted)
-		t.notifyError(err)
+// transition completes the task with the outcome of the delegate and the faults of attached children
+func (t *task) transition(result interface{}, err error) {
+	t.mutex.RLock()
+	childErrors := t.childErrors
+	t.mutex.RUnlock()
+	if len(childErrors) > 0 {
+		// the delegate error can be an AggregateError of another task, do not append to it
+		t.setFaulted(newAggregateError(append([]error{err}, childErrors...)...))
+		return
+	}
+
+	// transition the task and notify subscribers
+	// a delegate that returns the context error observed the cancellation
+	var canceled *canceledError
+	if errors.As(err, &canceled) {
+		t.setCanceled(canceled.err)
+	} else if err != nil && t.context.Err() != nil && errors.Is(err, t.context.Err()) {
+		t.setCanceled(err)
+	} else if err != nil {
+		t.setFaulted(err)
 	} else {
-		t.setResult(result)
-		t.setStatus(StatusSuccess)
-		t.notifyNext(result)
+		t.setSuccess(result)
+	}
+}
+
+// invoke calls the delegate and converts a panic into a PanicError unless panics are propagated
+func (t *task) invoke(ctx context.Context) (result interface{}, err error) {
+	atomic.AddInt32(&t.attempts, 1)
+	if !t.propagatePanics {
+		defer func() {
+			if r := recover(); r != nil {
+				result = nil
+				err = NewPanicError(r, debug.Stack(), t)
+			}
+		}()
+	}
+	return t.delegate(withCurrentTask(ctx, t), t.state)
+}
+
+// complete transitions the task to the given terminal status and closes the done channel.
+// Only the first caller wins the terminal transition, it returns false if the task was already complete.
+func (t *task) complete(status TaskStatus, result interface{}, err error) bool {
+	return t.finish(false, status, result, err)
+}
+
+// finish performs the terminal transition. If pendingOnly is set the transition only happens if the
+// delegate of the task is not running, the check and the transition are atomic.
+func (t *task) finish(pendingOnly bool, status TaskStatus, result interface{}, err error) bool {
+	t.mutex.Lock()
+	if pendingOnly && ((t.status == StatusRunning && !t.backoff) || t.status == StatusWaitingForChildren) {
+		t.mutex.Unlock()
+		return false
+	}
+	if !atomic.CompareAndSwapInt32(&t.terminal, 0, 1) {
+		t.mutex.Unlock()
+		return false
+	}
+	t.changeStatus(status)
+	t.result = result
+	t.err = err
+	cancel := t.cancel
+	t.mutex.Unlock()
+
+	// cleanup the channel after execution completes, this will activate any select statements
+	close(t.doneCh)
+
+	// release any resources held by the task context
+	if cancel != nil {
+		cancel()
+	}
+	return true
+}
+
+func (t *task) setSuccess(result interface{}) bool {
+	if !t.complete(StatusSuccess, result, nil) {
+		return false
+	}
+	t.tracker.NotifyTerminal(StatusSuccess, result, nil)
+	return true
+}
+
+func (t *task) setFaulted(err error) bool {
+	if !t.complete(StatusFaulted, nil, err) {
+		return false
+	}
+	t.tracker.NotifyTerminal(StatusFaulted, nil, err)
+	return true
+}
+
+func (t *task) setCanceled(err error) bool {
+	if !t.complete(StatusCanceled, nil, err) {
+		return false
 	}
+	t.tracker.NotifyTerminal(StatusCanceled, nil, err)
+	return true
+}
+
+func (t *task) Attempts() int {
+	return int(atomic.LoadInt32(&t.attempts))
+}
+
+func (t *task) Priority() int {
+	return t.priority
+}
+
+func (t *task) Cancel() {
+	// cancel the context first so a delegate that starts concurrently observes the cancellation
+	if t.cancelContext != nil {
+		t.cancelContext()
+	}
+	t.cancelPending(context.Canceled)
+}
+
+// cancellationRequested is called when the cancellation source of the task is canceled
+func (t *task) cancellationRequested() {
+	t.cancelPending(context.Canceled)
+}
+
+// isLazyCancellation returns true if cancellation must wait for the antecedent to complete
+func (t *task) isLazyCancellation() bool {
+	if t.continuationOptions == nil || !t.continuationOptions.lazyCancellation {
+		return false
+	}
+	return !t.antecedent.IsCompleted()
 }
 
 func (t *task) Wait() error {
@@ -154,27 +505,18 @@ func (t *task) Wait() error {
 	case <-t.doneCh:
 		return t.Error()
 	case <-t.context.Done():
-		err := t.context.Err()
-		t.setError(err)
-		t.setStatus(StatusCanceled)
-		t.notifyCanceled(err)
-		return err
 	}
-}
 
-func (t *task) notifyNext(value interface{}) {
-	t.tracker.NotifyNext(value)
-	t.tracker.NotifyCompleted()
-}
-
-func (t *task) notifyError(err error) {
-	t.tracker.NotifyError(err)
-	t.tracker.NotifyCompleted()
-}
+	// lazy continuations are canceled when the antecedent completes
+	if t.isLazyCancellation() {
+		<-t.doneCh
+		return t.Error()
+	}
 
-func (t *task) notifyCanceled(err error) {
-	t.tracker.NotifyCanceled(err)
-	t.tracker.NotifyCompleted()
+	// the cancellation wins the terminal transition and the result of a delegate that is still
+	// running is dropped. If the task completed concurrently the completed status wins.
+	t.setCanceled(t.context.Err())
+	return t.Error()
 }
 
 func (t *task) Result() interface{} {
@@ -183,12 +525,6 @@ func (t *task) Result() interface{} {
 	return t.result
 }
 
-func (t *task) setResult(result interface{}) {
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
-	t.result = result
-}
-
 func (t *task) Error() error {
 	t.mutex.RLock()
 	defer t.mutex.RUnlock()
@@ -228,13 +564,83 @@ func (t *task) Status() TaskStatus {
 	return t.status
 }
 
-func (t *task) setStatus(status TaskStatus) {
+func (t *task) Timestamps() Timestamps {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.timestamps
+}
+
+// transitionTo moves the task to a non terminal status and notifies status observers.
+// It returns false if the transition is not legal from the current status.
+func (t *task) transitionTo(status TaskStatus) bool {
 	t.mutex.Lock()
-	defer t.mutex.Unlock()
+	changed := t.changeStatus(status)
+	t.mutex.Unlock()
+	if changed {
+		t.tracker.NotifyStatus(status)
+	}
+	return changed
+}
+
+// changeStatus sets the status and the timestamp of the transition if the transition is legal.
+// The caller must hold the mutex.
+func (t *task) changeStatus(status TaskStatus) bool {
+	// once the terminal transition started the task can not move to another non terminal status
+	if atomic.LoadInt32(&t.terminal) != 0 && !isTerminal(status) {
+		return false
+	}
+	legal := false
+	for _, next := range transitions[t.status] {
+		if next == status {
+			legal = true
+			break
+		}
+	}
+	if !legal {
+		return false
+	}
 	t.status = status
+
+	now := t.now()
+	switch status {
+	case StatusWaitingToRun:
+		t.timestamps.Queued = now
+	case StatusRunning:
+		t.timestamps.Started = now
+	case StatusSuccess, StatusFaulted, StatusCanceled:
+		t.timestamps.Finished = now
+	}
+	return true
+}
+
+func (t *task) now() time.Time {
+	if t.clock == nil {
+		return DefaultClock().Now()
+	}
+	return t.clock.Now()
+}
+
+// queue transitions the task to StatusWaitingToRun and queues it on the scheduler.
+// A task whose context is already done is canceled instead.
+func (t *task) queue() {
+	if err := t.context.Err(); err != nil && t.cancelPending(err) {
+		return
+	}
+	t.transitionTo(StatusWaitingToRun)
+	t.scheduler.Queue(t)
+}
+
+func isTerminal(status TaskStatus) bool {
+	switch status {
+	case StatusSuccess, StatusFaulted, StatusCanceled:
+		return true
+	default:
+		return false
+	}
 }
 
-// Subscribe allows the observer to listen for update to the current task
+// Subscribe allows the observer to listen for updates to the current task. An observer that
+// subscribes to a completed task receives the terminal notification immediately.
 func (t *task) Subscribe(o Observer) io.Closer {
 	return t.tracker.Subscribe(o)
 }
@@ -255,83 +661,113 @@ func (t *task) OnCanceled(err error) {
 }
 
 func (t *task) OnCompleted() {
-	// close the tracker so this task no longer receives notifications
-	t.tracker.Close()
+	if t.antecedent == nil || t.continuationOptions == nil {
+		t.queue()
+		return
+	}
 
-	// schedule this task irrespective of its status
-	// we want the delegates with task parameters to handle the errors
-	t.scheduler.Queue(t)
+	// skipped continuations transition to canceled without running
+	if !t.continuationOptions.runsOn(t.antecedent.Status()) {
+		t.setCanceled(context.Canceled)
+		return
+	}
+
+	if t.continuationOptions.executeSynchronously {
+		t.Start()
+		return
+	}
+	t.queue()
 }
 
-func (t *task) ContinueAction(continueAction ContinueAction) Task {
+func (t *task) ContinueAction(continueAction ContinueAction, options ...ContinuationOption) Task {
 	f := func(t Task, state interface{}) (interface{}, error) {
 		continueAction(t)
 		return nil, nil
 	}
-	return t.ContinueErrFuncWith(f)
+	return t.ContinueErrFuncWith(f, options...)
 }
 
-func (t *task) ContinueActionWith(continueActionWith ContinueActionWith) Task {
+func (t *task) ContinueActionWith(continueActionWith ContinueActionWith, options ...ContinuationOption) Task {
 	f := func(t Task, state interface{}) (interface{}, error) {
 		continueActionWith(t, state)
 		return nil, nil
 	}
-	return t.ContinueErrFuncWith(f)
+	return t.ContinueErrFuncWith(f, options...)
 }
 
-func (t *task) ContinueErrAction(continueErrAction ContinueErrAction) Task {
+func (t *task) ContinueErrAction(continueErrAction ContinueErrAction, options ...ContinuationOption) Task {
 	f := func(t Task, state interface{}) (interface{}, error) {
 		return nil, continueErrAction(t)
 	}
-	return t.ContinueErrFuncWith(f)
+	return t.ContinueErrFuncWith(f, options...)
 }
 
-func (t *task) ContinueErrActionWith(continueErrActionWith ContinueErrActionWith) Task {
+func (t *task) ContinueErrActionWith(continueErrActionWith ContinueErrActionWith, options ...ContinuationOption) Task {
 	f := func(t Task, state interface{}) (interface{}, error) {
 		return nil, continueErrActionWith(t, state)
 	}
-	return t.ContinueErrFuncWith(f)
+	return t.ContinueErrFuncWith(f, options...)
 }
 
-func (t *task) ContinueFunc(continueFunc ContinueFunc) Task {
+func (t *task) ContinueFunc(continueFunc ContinueFunc, options ...ContinuationOption) Task {
 	f := func(t Task, state interface{}) (interface{}, error) {
 		return continueFunc(t), nil
 	}
-	return t.ContinueErrFuncWith(f)
+	return t.ContinueErrFuncWith(f, options...)
 }
 
-func (t *task) ContinueFuncWith(continueFuncWith ContinueFuncWith) Task {
+func (t *task) ContinueFuncWith(continueFuncWith ContinueFuncWith, options ...ContinuationOption) Task {
 	f := func(t Task, state interface{}) (interface{}, error) {
 		return continueFuncWith(t, state), nil
 	}
-	return t.ContinueErrFuncWith(f)
+	return t.ContinueErrFuncWith(f, options...)
 }
 
-func (t *task) ContinueErrFunc(continueErrFunc ContinueErrFunc) Task {
+func (t *task) ContinueErrFunc(continueErrFunc ContinueErrFunc, options ...ContinuationOption) Task {
 	f := func(t Task, state interface{}) (interface{}, error) {
 		return continueErrFunc(t)
 	}
-	return t.ContinueErrFuncWith(f)
+	return t.ContinueErrFuncWith(f, options...)
 }
 
-func (t *task) ContinueErrFuncWith(continueErrFuncWith ContinueErrFuncWith) Task {
-	errFuncWith := func(state interface{}) (interface{}, error) {
+func (t *task) ContinueErrFuncWith(continueErrFuncWith ContinueErrFuncWith, options ...ContinuationOption) Task {
+	f := func(_ context.Context, t Task, state interface{}) (interface{}, error) {
 		return continueErrFuncWith(t, state)
 	}
-	continuation := new(errFuncWith)
-	if t.context != nil {
-		continuation.context = t.context
+	return t.ContinueContextErrFuncWith(f, options...)
+}
+
+func (t *task) ContinueContextErrAction(continueContextErrAction ContinueContextErrAction, options ...ContinuationOption) Task {
+	f := func(ctx context.Context, t Task, state interface{}) (interface{}, error) {
+		return nil, continueContextErrAction(ctx, t)
 	}
+	return t.ContinueContextErrFuncWith(f, options...)
+}
+
+func (t *task) ContinueContextErrFuncWith(continueContextErrFuncWith ContinueContextErrFuncWith, options ...ContinuationOption) Task {
+	delegate := func(ctx context.Context, state interface{}) (interface{}, error) {
+		return continueContextErrFuncWith(ctx, t, state)
+	}
+	opts := newContinuationOptions(options...)
+	continuation := new(delegate)
+	// the continuation does not observe the cancellation of the antecedent, only CancelOn cancels it
 	if t.scheduler != nil {
 		continuation.scheduler = t.scheduler
 	}
-
-	// if the current task is already complete, immediately schedule the continuation
-	// otherwise setup a subscription
-	if t.IsCompleted() {
-		continuation.scheduler.Queue(continuation)
-	} else {
-		t.Subscribe(continuation)
+	if opts.scheduler != nil {
+		continuation.scheduler = opts.scheduler
+	}
+	if opts.inheritPriority {
+		continuation.priority = t.Priority()
 	}
+	continuation.antecedent = t
+	continuation.continuationOptions = opts
+	if opts.cancellation != nil {
+		WithCancellation(opts.cancellation)(continuation)
+	}
+	continuation.apply()
+
+	// the subscription replays the completion if the current task is already complete
+	t.Subscribe(continuation)
 	return continuation
 }
@@ -0,0 +1,48 @@
+package task
+
+import "fmt"
+
+// TaskError attributes an error to a task in the list of tasks passed to a combinator such as WhenAll
+type TaskError interface {
+	error
+	// Index returns the index of the task in the list of tasks
+	Index() int
+	// Task returns the task that produced the error
+	Task() Task
+	// Unwrap returns the error of the task
+	Unwrap() error
+}
+
+type taskError struct {
+	index int
+	task  Task
+	err   error
+}
+
+// NewTaskError creates a TaskError for the task at the given index
+func NewTaskError(index int, t Task, err error) TaskError {
+	return &taskError{
+		index: index,
+		task:  t,
+		err:   err,
+	}
+}
+
+func (err *taskError) Error() string {
+	if err.err == nil {
+		return fmt.Sprintf("task %d: %s", err.index, err.task.Status())
+	}
+	return fmt.Sprintf("task %d: %s", err.index, err.err.Error())
+}
+
+func (err *taskError) Index() int {
+	return err.index
+}
+
+func (err *taskError) Task() Task {
+	return err.task
+}
+
+func (err *taskError) Unwrap() error {
+	return err.err
+}
@@ -179,4 +179,51 @@ var _ = Describe("Task", func() {
 		})
 
 	})
+	Describe("ContextErrAction", func() {
+		It("observes cancellation", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			started := make(chan struct{})
+			stopped := make(chan struct{})
+			t := task.RunContextErrAction(func(ctx context.Context) error {
+				defer close(stopped)
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			}, task.WithContext(ctx))
+			<-started
+			cancel()
+			Eventually(stopped).Should(BeClosed())
+			Expect(t.Wait()).To(Equal(context.Canceled))
+			Expect(t.IsCanceled()).To(BeTrue())
+		})
+		It("observes timeout", func() {
+			t := task.RunContextErrAction(func(ctx context.Context) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Second):
+					return nil
+				}
+			}, task.WithTimeout(time.Millisecond))
+			Expect(t.Wait()).To(Equal(context.DeadlineExceeded))
+			Expect(t.IsCanceled()).To(BeTrue())
+		})
+		It("faults on other error", func() {
+			t := task.RunContextErrAction(func(ctx context.Context) error {
+				return fmt.Errorf("error")
+			})
+			Expect(t.Wait()).ToNot(BeNil())
+			Expect(t.IsFaulted()).To(BeTrue())
+		})
+	})
+	Describe("ContextErrFuncWith", func() {
+		It("can roundtrip state", func() {
+			t := task.RunContextErrFuncWith(func(ctx context.Context, state interface{}) (interface{}, error) {
+				Expect(ctx).ToNot(BeNil())
+				return state, nil
+			}, task.WithState(1))
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(1))
+		})
+	})
 })
@@ -0,0 +1,149 @@
+package tasktest
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/patrickhuber/go-task"
+)
+
+// Clock is a virtual task.Clock. Time only moves when AdvanceBy or AdvanceTo is called
+// and timers fire on the routine that advances the clock.
+type Clock interface {
+	task.Clock
+	// AdvanceBy moves the clock forward by the duration and fires the timers that are due
+	AdvanceBy(d time.Duration)
+	// AdvanceTo moves the clock forward to the time and fires the timers that are due.
+	// Times in the past do not move the clock.
+	AdvanceTo(t time.Time)
+}
+
+type clock struct {
+	mutex    sync.Mutex
+	now      time.Time
+	timers   timerQueue
+	sequence uint64
+}
+
+type timer struct {
+	clock    *clock
+	due      time.Time
+	sequence uint64
+	callback func()
+	index    int
+}
+
+// NewClock creates a virtual clock starting at the given time
+func NewClock(start time.Time) Clock {
+	return &clock{
+		now:    start,
+		timers: timerQueue{},
+	}
+}
+
+func (c *clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *clock) AfterFunc(d time.Duration, f func()) task.Timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sequence++
+	t := &timer{
+		clock:    c,
+		due:      c.now.Add(d),
+		sequence: c.sequence,
+		callback: f,
+	}
+	heap.Push(&c.timers, t)
+	return t
+}
+
+func (c *clock) AdvanceBy(d time.Duration) {
+	c.AdvanceTo(c.Now().Add(d))
+}
+
+func (c *clock) AdvanceTo(t time.Time) {
+	for c.fireNext(t) {
+	}
+	c.moveTo(t)
+}
+
+// fireNext fires the next timer due at or before the given time. It moves the clock to the
+// due time of the timer and returns false if no timer is due.
+func (c *clock) fireNext(t time.Time) bool {
+	c.mutex.Lock()
+	if len(c.timers) == 0 || c.timers[0].due.After(t) {
+		c.mutex.Unlock()
+		return false
+	}
+	next := heap.Pop(&c.timers).(*timer)
+	if next.due.After(c.now) {
+		c.now = next.due
+	}
+	c.mutex.Unlock()
+
+	// fire outside of the lock so the callback can use the clock
+	next.callback()
+	return true
+}
+
+func (c *clock) moveTo(t time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+}
+
+func (t *timer) Stop() bool {
+	c := t.clock
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// the index is negative after the timer is removed from the queue
+	if t.index < 0 {
+		return false
+	}
+	heap.Remove(&c.timers, t.index)
+	return true
+}
+
+// timerQueue implements heap.Interface ordered by due time and creation order
+type timerQueue []*timer
+
+func (q timerQueue) Len() int {
+	return len(q)
+}
+
+func (q timerQueue) Less(i, j int) bool {
+	if !q[i].due.Equal(q[j].due) {
+		return q[i].due.Before(q[j].due)
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+func (q timerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *timerQueue) Push(x interface{}) {
+	t := x.(*timer)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+
+func (q *timerQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*q = old[:n-1]
+	return t
+}
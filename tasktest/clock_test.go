@@ -0,0 +1,68 @@
+package tasktest_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/patrickhuber/go-task"
+	"github.com/patrickhuber/go-task/tasktest"
+)
+
+var _ = Describe("Clock", func() {
+	var (
+		start time.Time
+		clock tasktest.Clock
+	)
+	BeforeEach(func() {
+		start = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock = tasktest.NewClock(start)
+	})
+	It("does not move without advance", func() {
+		Expect(clock.Now()).To(Equal(start))
+	})
+	It("can advance by", func() {
+		clock.AdvanceBy(time.Second)
+		Expect(clock.Now()).To(Equal(start.Add(time.Second)))
+	})
+	It("can advance to", func() {
+		clock.AdvanceTo(start.Add(time.Minute))
+		Expect(clock.Now()).To(Equal(start.Add(time.Minute)))
+		clock.AdvanceTo(start)
+		Expect(clock.Now()).To(Equal(start.Add(time.Minute)))
+	})
+	It("fires timers in order", func() {
+		fired := []int{}
+		clock.AfterFunc(2*time.Second, func() { fired = append(fired, 2) })
+		clock.AfterFunc(time.Second, func() { fired = append(fired, 1) })
+		clock.AfterFunc(3*time.Second, func() { fired = append(fired, 3) })
+		clock.AdvanceBy(2 * time.Second)
+		Expect(fired).To(Equal([]int{1, 2}))
+		clock.AdvanceBy(time.Second)
+		Expect(fired).To(Equal([]int{1, 2, 3}))
+	})
+	It("fires timers at due time", func() {
+		var now time.Time
+		clock.AfterFunc(time.Second, func() { now = clock.Now() })
+		clock.AdvanceBy(time.Minute)
+		Expect(now).To(Equal(start.Add(time.Second)))
+	})
+	It("can stop timer", func() {
+		count := 0
+		timer := clock.AfterFunc(time.Second, func() { count++ })
+		Expect(timer.Stop()).To(BeTrue())
+		Expect(timer.Stop()).To(BeFalse())
+		clock.AdvanceBy(time.Second)
+		Expect(count).To(Equal(0))
+	})
+	It("drives cancel after", func() {
+		source := task.NewCancellationSource(task.WithCancellationClock(clock))
+		defer source.Close()
+		source.CancelAfter(time.Second)
+		clock.AdvanceBy(time.Second - time.Nanosecond)
+		Expect(source.IsCancellationRequested()).To(BeFalse())
+		clock.AdvanceBy(time.Nanosecond)
+		Expect(source.IsCancellationRequested()).To(BeTrue())
+	})
+})
@@ -0,0 +1,96 @@
+package tasktest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/patrickhuber/go-task"
+)
+
+// VirtualScheduler runs queued tasks in FIFO order on the routine that calls RunUntilIdle,
+// AdvanceBy or AdvanceTo. Tasks queued on the scheduler use its virtual clock for timeouts.
+type VirtualScheduler interface {
+	task.Scheduler
+	// Clock returns the virtual clock of the scheduler
+	Clock() task.Clock
+	// Now returns the current virtual time
+	Now() time.Time
+	// AdvanceBy moves the virtual clock forward by the duration. Due timers fire in order and
+	// the scheduler runs until idle after each timer.
+	AdvanceBy(d time.Duration)
+	// AdvanceTo moves the virtual clock forward to the time. Due timers fire in order and
+	// the scheduler runs until idle after each timer.
+	AdvanceTo(t time.Time)
+	// RunUntilIdle runs queued tasks and timers due at the current time until there is no work left
+	RunUntilIdle()
+}
+
+type virtualScheduler struct {
+	mutex sync.Mutex
+	tasks []task.Task
+	clock *clock
+}
+
+// NewVirtualScheduler creates a virtual scheduler with a virtual clock starting at the given time
+func NewVirtualScheduler(start time.Time) VirtualScheduler {
+	return &virtualScheduler{
+		tasks: []task.Task{},
+		clock: NewClock(start).(*clock),
+	}
+}
+
+func (s *virtualScheduler) Queue(t task.Task) {
+	// do not schedule completed tasks
+	if t.IsCompleted() {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.tasks = append(s.tasks, t)
+}
+
+func (s *virtualScheduler) Clock() task.Clock {
+	return s.clock
+}
+
+func (s *virtualScheduler) Now() time.Time {
+	return s.clock.Now()
+}
+
+func (s *virtualScheduler) AdvanceBy(d time.Duration) {
+	s.AdvanceTo(s.clock.Now().Add(d))
+}
+
+func (s *virtualScheduler) AdvanceTo(t time.Time) {
+	s.RunUntilIdle()
+	for s.clock.fireNext(t) {
+		s.RunUntilIdle()
+	}
+	s.clock.moveTo(t)
+	s.RunUntilIdle()
+}
+
+func (s *virtualScheduler) RunUntilIdle() {
+	for {
+		if s.clock.fireNext(s.clock.Now()) {
+			continue
+		}
+		t, ok := s.dequeue()
+		if !ok {
+			return
+		}
+		t.Start()
+	}
+}
+
+func (s *virtualScheduler) dequeue() (task.Task, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.tasks) == 0 {
+		return nil, false
+	}
+	t := s.tasks[0]
+	s.tasks[0] = nil
+	s.tasks = s.tasks[1:]
+	return t, true
+}
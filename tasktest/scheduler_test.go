@@ -0,0 +1,91 @@
+package tasktest_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/patrickhuber/go-task"
+	"github.com/patrickhuber/go-task/tasktest"
+)
+
+var _ = Describe("VirtualScheduler", func() {
+	var (
+		start     time.Time
+		scheduler tasktest.VirtualScheduler
+	)
+	BeforeEach(func() {
+		start = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		scheduler = tasktest.NewVirtualScheduler(start)
+	})
+	It("runs tasks in order", func() {
+		order := []int{}
+		for i := 0; i < 3; i++ {
+			task.RunActionWith(func(state interface{}) {
+				order = append(order, state.(int))
+			}, task.WithState(i), task.WithScheduler(scheduler))
+		}
+		Expect(order).To(BeEmpty())
+		scheduler.RunUntilIdle()
+		Expect(order).To(Equal([]int{0, 1, 2}))
+	})
+	It("runs continuations", func() {
+		t := task.RunFunc(func() interface{} {
+			return 1
+		}, task.WithScheduler(scheduler)).Then(func(result interface{}) (interface{}, error) {
+			return result.(int) + 1, nil
+		})
+		scheduler.RunUntilIdle()
+		Expect(t.IsCompleted()).To(BeTrue())
+		Expect(t.Result()).To(Equal(2))
+	})
+	It("completes delay", func() {
+		t := task.Delay(time.Hour, task.WithScheduler(scheduler))
+		scheduler.AdvanceBy(time.Hour - time.Second)
+		Expect(t.IsCompleted()).To(BeFalse())
+		scheduler.AdvanceBy(time.Second)
+		Expect(t.IsSuccess()).To(BeTrue())
+		Expect(scheduler.Now()).To(Equal(start.Add(time.Hour)))
+	})
+	It("runs delay continuations at the due time", func() {
+		var now time.Time
+		task.Delay(time.Minute, task.WithScheduler(scheduler)).ContinueAction(func(task.Task) {
+			now = scheduler.Now()
+		})
+		scheduler.AdvanceBy(time.Hour)
+		Expect(now).To(Equal(start.Add(time.Minute)))
+	})
+	It("times out tasks", func() {
+		count := 0
+		t := task.NewContextErrAction(func(ctx context.Context) error {
+			count++
+			return nil
+		}, task.WithTimeout(time.Second), task.WithScheduler(scheduler))
+		scheduler.AdvanceTo(start.Add(time.Second))
+		scheduler.Queue(t)
+		scheduler.RunUntilIdle()
+		Expect(count).To(Equal(0))
+		Expect(t.Wait()).To(Equal(context.DeadlineExceeded))
+		Expect(t.IsCanceled()).To(BeTrue())
+	})
+	It("does not time out before deadline", func() {
+		t := task.RunContextErrAction(func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			Expect(ok).To(BeTrue())
+			Expect(deadline).To(Equal(start.Add(time.Second)))
+			return ctx.Err()
+		}, task.WithTimeout(time.Second), task.WithScheduler(scheduler))
+		scheduler.RunUntilIdle()
+		Expect(t.Wait()).To(BeNil())
+	})
+	It("resolves WhenAny deterministically", func() {
+		slow := task.Delay(time.Second, task.WithScheduler(scheduler))
+		fast := task.Delay(time.Millisecond, task.WithScheduler(scheduler))
+		t := task.WhenAny(slow, fast)
+		scheduler.AdvanceBy(time.Millisecond)
+		Expect(t.IsCompleted()).To(BeTrue())
+		Expect(slow.IsCompleted()).To(BeFalse())
+	})
+})
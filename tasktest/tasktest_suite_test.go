@@ -0,0 +1,13 @@
+package tasktest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTaskTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TaskTest Suite")
+}
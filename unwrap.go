@@ -0,0 +1,72 @@
+package task
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotTask faults an unwrapped task when the result of the outer task is not a Task
+var ErrNotTask = errors.New("result of the outer task is not a task")
+
+// Unwrap returns a proxy task for a task whose result is another task. The proxy completes when the
+// inner task completes with the result, fault or cancellation of the inner task. If the outer task
+// faults or is canceled the proxy completes with the outcome of the outer task. A nil inner task
+// cancels the proxy.
+func Unwrap(outer Task) Task {
+	proxy := new(nil)
+	proxy.apply()
+	outer.Subscribe(NewObserver(nil, func() {
+		proxy.unwrap(outer)
+	}, nil, nil))
+	return proxy
+}
+
+// unwrap subscribes to the inner task once the outer task completes
+func (t *task) unwrap(outer Task) {
+	if !outer.IsSuccess() {
+		t.completeWith(outer)
+		return
+	}
+	result := outer.Result()
+	if result == nil {
+		t.setCanceled(context.Canceled)
+		return
+	}
+	inner, ok := result.(Task)
+	if !ok {
+		t.setFaulted(ErrNotTask)
+		return
+	}
+	inner.Subscribe(NewObserver(nil, func() {
+		t.completeWith(inner)
+	}, nil, nil))
+}
+
+// completeWith completes the task with the outcome of the completed source task
+func (t *task) completeWith(source Task) {
+	switch source.Status() {
+	case StatusCanceled:
+		t.setCanceled(source.Error())
+	case StatusFaulted:
+		t.setFaulted(source.Error())
+	default:
+		t.setSuccess(source.Result())
+	}
+}
+
+func (t *task) ContinueWithTask(continueWithTask ContinueWithTask, options ...ContinuationOption) Task {
+	outer := t.ContinueFunc(func(antecedent Task) interface{} {
+		return continueWithTask(antecedent)
+	}, options...)
+	return Unwrap(outer)
+}
+
+func (t *task) Bind(bind Bind) Task {
+	outer := t.ContinueErrFunc(func(antecedent Task) (interface{}, error) {
+		if !antecedent.IsSuccess() {
+			return propagate(antecedent)
+		}
+		return bind(antecedent.Result()), nil
+	})
+	return Unwrap(outer)
+}
@@ -0,0 +1,93 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("Unwrap", func() {
+	It("completes with inner result", func() {
+		outer := task.RunFunc(func() interface{} {
+			return task.RunFunc(func() interface{} {
+				return 1
+			})
+		})
+		t := task.Unwrap(outer)
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(1))
+	})
+	It("waits for inner task", func() {
+		inner := task.NewCompletionSource()
+		t := task.Unwrap(task.FromResult(inner.Task()))
+		Consistently(t.IsCompleted, "20ms").Should(BeFalse())
+		Expect(inner.SetResult(1)).To(BeNil())
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal(1))
+	})
+	It("propagates inner fault", func() {
+		err := fmt.Errorf("error")
+		t := task.Unwrap(task.FromResult(task.FromError(err)))
+		Expect(t.Wait()).To(Equal(err))
+		Expect(t.IsFaulted()).To(BeTrue())
+	})
+	It("propagates inner cancellation", func() {
+		t := task.Unwrap(task.FromResult(task.FromCanceled(nil)))
+		t.Wait()
+		Expect(t.IsCanceled()).To(BeTrue())
+	})
+	It("propagates outer fault", func() {
+		err := fmt.Errorf("error")
+		t := task.Unwrap(task.FromError(err))
+		Expect(t.Wait()).To(Equal(err))
+	})
+	It("cancels on nil inner task", func() {
+		t := task.Unwrap(task.Completed())
+		Expect(t.Wait()).To(Equal(context.Canceled))
+		Expect(t.IsCanceled()).To(BeTrue())
+	})
+	It("faults when result is not a task", func() {
+		t := task.Unwrap(task.FromResult(1))
+		Expect(t.Wait()).To(Equal(task.ErrNotTask))
+	})
+	Describe("ContinueWithTask", func() {
+		It("completes with inner result", func() {
+			t := task.FromResult(1).ContinueWithTask(func(antecedent task.Task) task.Task {
+				return task.RunFunc(func() interface{} {
+					return antecedent.Result().(int) + 1
+				})
+			})
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(2))
+		})
+		It("cancels skipped continuation", func() {
+			t := task.FromResult(1).ContinueWithTask(func(task.Task) task.Task {
+				return task.FromResult(2)
+			}, task.OnlyOnFaulted())
+			t.Wait()
+			Expect(t.IsCanceled()).To(BeTrue())
+		})
+	})
+	Describe("Bind", func() {
+		It("binds result", func() {
+			t := task.FromResult(1).Bind(func(result interface{}) task.Task {
+				return task.FromResult(result.(int) + 1)
+			})
+			Expect(t.Wait()).To(BeNil())
+			Expect(t.Result()).To(Equal(2))
+		})
+		It("propagates fault without running", func() {
+			err := fmt.Errorf("error")
+			ran := false
+			t := task.FromError(err).Bind(func(interface{}) task.Task {
+				ran = true
+				return task.Completed()
+			})
+			Expect(t.Wait()).To(Equal(err))
+			Expect(ran).To(BeFalse())
+		})
+	})
+})
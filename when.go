@@ -2,6 +2,7 @@ package task
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 )
 
@@ -9,49 +10,287 @@ type whenTask struct {
 	task
 	tasks     []Task
 	remaining int32
+	// collect sets the result to the ordered slice of task results
+	collect bool
+	// resolved is set when the outcome of the when task is decided
+	resolved  int32
+	succeeded int32
+	failed    int32
 }
 
-// WhenAny creates a task that completes when any task in the list completes
+// WhenAnyResult is the result of a task created by WhenAny
+type WhenAnyResult interface {
+	// Index returns the index of the first completed task
+	Index() int
+	// Task returns the first completed task
+	Task() Task
+}
+
+type whenAnyResult struct {
+	index int
+	task  Task
+}
+
+func (r *whenAnyResult) Index() int {
+	return r.index
+}
+
+func (r *whenAnyResult) Task() Task {
+	return r.task
+}
+
+// WhenAny creates a task that completes when any task in the list completes. The task completes
+// with the status of the first completed task. On success the result is a WhenAnyResult, faults and
+// cancellation of the first completed task are reported with a TaskError. An empty list has no
+// first task, the returned task faults with ErrNoTasks.
 func WhenAny(tasks ...Task) Task {
-	return when(1, tasks...)
+	return whenAny(false, tasks...)
+}
+
+// WhenAnyCancelRemaining is WhenAny that cancels the remaining tasks once the first task completes.
+// Remaining tasks that have not started are canceled, running delegates observe their context and
+// complete their tasks when they return.
+func WhenAnyCancelRemaining(tasks ...Task) Task {
+	return whenAny(true, tasks...)
+}
+
+// ErrNoTasks faults a WhenAny task that was given no tasks
+var ErrNoTasks = errors.New("no tasks to complete")
+
+func whenAny(cancelRemaining bool, tasks ...Task) Task {
+	if len(tasks) == 0 {
+		return FromError(ErrNoTasks)
+	}
+	when := newWhen(tasks)
+	when.observe(func(index int) {
+		when.first(index, cancelRemaining)
+	})
+	return when
 }
 
-// WhenAll creates a task that completes when all tasks in the list complete
+// WhenAll creates a task that completes when all tasks in the list complete. On success the result
+// is a []interface{} with the task results in the order of the list. Errors of faulted or canceled
+// tasks are aggregated as TaskErrors that identify the index of the task.
 func WhenAll(tasks ...Task) Task {
-	return when(len(tasks), tasks...)
+	if len(tasks) == 0 {
+		return FromResult([]interface{}{})
+	}
+	return when(len(tasks), true, tasks...)
+}
+
+// WhenAllFailFast creates a task that completes when all tasks in the list complete successfully
+// or when the first task faults or is canceled. On the first failure the when task completes with an
+// AggregateError of the failures observed at that time and the remaining tasks are canceled, see Cancel.
+// On success the result is a []interface{} with the task results in the order of the list.
+func WhenAllFailFast(tasks ...Task) Task {
+	if len(tasks) == 0 {
+		return FromResult([]interface{}{})
+	}
+	when := newWhen(tasks)
+	when.collect = true
+	when.observe(when.failFast)
+	return when
+}
+
+// Outcome records how a task completed
+type Outcome struct {
+	Status TaskStatus
+	Result interface{}
+	Err    error
+}
+
+// WhenAllSettled creates a task that completes when all tasks in the list complete. It never faults,
+// the result is a []Outcome with the outcome of each task in the order of the list.
+func WhenAllSettled(tasks ...Task) Task {
+	if len(tasks) == 0 {
+		return FromResult([]Outcome{})
+	}
+	when := newWhen(tasks)
+	when.remaining = int32(len(tasks))
+	when.observe(func(int) {
+		when.settled()
+	})
+	return when
+}
+
+// ErrQuorumUnreachable faults a WhenN task that requires more successful tasks than it was given
+var ErrQuorumUnreachable = errors.New("quorum is larger than the number of tasks")
+
+// WhenN creates a task that succeeds once k tasks succeed. It faults as soon as success becomes
+// impossible, that is when more than len(tasks)-k tasks fault or are canceled. On success the result
+// is a []interface{} with the results of k successful tasks in the order of the list. The fault is an
+// AggregateError with a TaskError for each failed task.
+func WhenN(k int, tasks ...Task) Task {
+	if k <= 0 {
+		return FromResult([]interface{}{})
+	}
+	if k > len(tasks) {
+		return FromError(ErrQuorumUnreachable)
+	}
+	when := newWhen(tasks)
+	when.observe(func(index int) {
+		when.quorum(index, k)
+	})
+	return when
 }
 
 // When creates a task that completes when the limit of tasks complete
-func when(limit int, tasks ...Task) Task {
+func when(limit int, collect bool, tasks ...Task) Task {
 	if len(tasks) == 0 {
 		return Completed()
 	}
-	when := &whenTask{
-		tasks:     tasks,
-		remaining: int32(limit),
+	when := newWhen(tasks)
+	when.remaining = int32(limit)
+	when.collect = collect
+	when.observe(func(int) {
+		when.completed()
+	})
+	return when
+}
+
+func newWhen(tasks []Task) *whenTask {
+	return &whenTask{
+		tasks: tasks,
 		task: task{
-			tracker: NewTracker(),
-			context: context.TODO(),
+			status:     StatusCreated,
+			timestamps: Timestamps{Created: DefaultClock().Now()},
+			tracker:    NewTracker(),
+			context:    context.TODO(),
 			// use a buffered channel to avoid blocking caller
 			doneCh: make(chan struct{}, 1),
 		},
 	}
+}
 
-	for _, t := range tasks {
+// observe calls completed with the index of each task when the task completes.
+// Each task gets its own observer so the same task can be passed more than once.
+func (t *whenTask) observe(completed func(index int)) {
+	for i, tsk := range t.tasks {
+		index := i
+		// completed tasks replay the completion to the observer
+		tsk.Subscribe(NewObserver(nil, func() {
+			completed(index)
+		}, nil, nil))
+	}
+}
 
-		// bypass the subscription if the task is completed
-		if t.IsCompleted() {
-			when.OnCompleted()
-			continue
+// settled completes the when task with the outcomes of the tasks once all tasks complete
+func (t *whenTask) settled() {
+	if atomic.AddInt32(&t.remaining, -1) != 0 {
+		return
+	}
+	outcomes := make([]Outcome, len(t.tasks))
+	for i, tsk := range t.tasks {
+		outcomes[i] = Outcome{
+			Status: tsk.Status(),
+			Result: tsk.Result(),
+			Err:    tsk.Error(),
 		}
-		t.Subscribe(when)
 	}
+	t.setSuccess(outcomes)
+}
 
-	return when
+// failFast resolves the when task when all tasks succeed or when the task at the index fails
+func (t *whenTask) failFast(index int) {
+	current := t.tasks[index]
+	if current.IsSuccess() {
+		if atomic.AddInt32(&t.succeeded, 1) != int32(len(t.tasks)) {
+			return
+		}
+		if !atomic.CompareAndSwapInt32(&t.resolved, 0, 1) {
+			return
+		}
+		t.setSuccess(t.results())
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&t.resolved, 0, 1) {
+		return
+	}
+
+	// collect the faults before canceling the remaining tasks, their cancellation is not a failure
+	err := AppendError(NewTaskError(index, current, current.Error()))
+	for i, tsk := range t.tasks {
+		if i != index && tsk.IsFaulted() {
+			err.Append(NewTaskError(i, tsk, tsk.Error()))
+		}
+	}
+	for _, tsk := range t.tasks {
+		if !tsk.IsCompleted() {
+			tsk.Cancel()
+		}
+	}
+	if current.IsCanceled() {
+		t.setCanceled(err)
+		return
+	}
+	t.setFaulted(err)
 }
 
-func (t *whenTask) IsCompleted() bool {
-	return t.remaining == 0
+// quorum counts the outcome of the task at the index and resolves the when task when k tasks
+// succeed or when too many tasks failed for k tasks to succeed
+func (t *whenTask) quorum(index int, k int) {
+	if t.tasks[index].IsSuccess() {
+		if atomic.AddInt32(&t.succeeded, 1) != int32(k) {
+			return
+		}
+		if !atomic.CompareAndSwapInt32(&t.resolved, 0, 1) {
+			return
+		}
+		results := []interface{}{}
+		for _, tsk := range t.tasks {
+			if len(results) == k {
+				break
+			}
+			if tsk.IsSuccess() {
+				results = append(results, tsk.Result())
+			}
+		}
+		t.setSuccess(results)
+		return
+	}
+
+	if atomic.AddInt32(&t.failed, 1) != int32(len(t.tasks)-k+1) {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&t.resolved, 0, 1) {
+		return
+	}
+	var err error
+	for i, tsk := range t.tasks {
+		if tsk.IsFaulted() || tsk.IsCanceled() {
+			err = AppendError(err, NewTaskError(i, tsk, tsk.Error()))
+		}
+	}
+	t.setFaulted(err)
+}
+
+// first completes the when task with the outcome of the first completed task
+func (t *whenTask) first(index int, cancelRemaining bool) {
+	if !atomic.CompareAndSwapInt32(&t.resolved, 0, 1) {
+		return
+	}
+	winner := t.tasks[index]
+
+	// cancel the remaining tasks before notifying observers of the when task
+	if cancelRemaining {
+		for _, tsk := range t.tasks {
+			if tsk != winner {
+				tsk.Cancel()
+			}
+		}
+	}
+
+	switch winner.Status() {
+	case StatusCanceled:
+		t.setCanceled(NewTaskError(index, winner, winner.Error()))
+	case StatusFaulted:
+		t.setFaulted(NewTaskError(index, winner, winner.Error()))
+	default:
+		t.setSuccess(&whenAnyResult{
+			index: index,
+			task:  winner,
+		})
+	}
 }
 
 func (t *whenTask) Execute() {
@@ -63,45 +302,41 @@ func (t *whenTask) OnNext(value interface{}) {
 }
 
 func (t *whenTask) OnCompleted() {
+}
+
+func (t *whenTask) completed() {
 	if atomic.AddInt32(&t.remaining, -1) != 0 {
 		return
 	}
 
 	// start in a success state
-	t.setStatus(StatusSuccess)
+	status := StatusSuccess
+	var err error
 
 	// the remaining tasks are complete, process them
-	// for WhenAll this is all tasks
-	// for WhenAny this is any completed task
 	for i := 0; i < len(t.tasks); i++ {
 		tsk := t.tasks[i]
 		if tsk.IsFaulted() {
-			t.setStatus(StatusFaulted)
+			status = StatusFaulted
 			if tsk.Error() != nil {
-				t.setError(AppendError(t.Error(), tsk.Error()))
+				err = AppendError(err, NewTaskError(i, tsk, tsk.Error()))
 			}
 		} else if tsk.IsCanceled() {
-			t.setStatus(StatusCanceled)
+			status = StatusCanceled
 			if tsk.Error() != nil {
-				t.setError(AppendError(t.Error(), tsk.Error()))
+				err = AppendError(err, NewTaskError(i, tsk, tsk.Error()))
 			}
 		}
 	}
 
-	// clear all subscriptions
-	defer t.tracker.Close()
-
-	switch t.Status() {
+	switch status {
 	case StatusCanceled:
-		t.notifyCanceled(t.Error())
+		t.setCanceled(err)
 	case StatusFaulted:
-		t.notifyError(t.Error())
+		t.setFaulted(err)
 	case StatusSuccess:
-		t.notifyNext(t.Result())
+		t.setSuccess(t.results())
 	}
-
-	// notify that we are done by closing the channel
-	defer close(t.doneCh)
 }
 
 func (t *whenTask) OnError(err error) {
@@ -109,3 +344,15 @@ func (t *whenTask) OnError(err error) {
 
 func (t *whenTask) OnCanceled(err error) {
 }
+
+// results returns the ordered results of the tasks if the when task collects results
+func (t *whenTask) results() interface{} {
+	if !t.collect {
+		return nil
+	}
+	results := make([]interface{}, len(t.tasks))
+	for i, tsk := range t.tasks {
+		results[i] = tsk.Result()
+	}
+	return results
+}
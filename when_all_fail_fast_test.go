@@ -0,0 +1,66 @@
+package task_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("WhenAllFailFast", func() {
+	It("returns results when all tasks succeed", func() {
+		source := task.NewCompletionSource()
+		t := task.WhenAllFailFast(task.FromResult(1), source.Task())
+		Expect(t.IsCompleted()).To(BeFalse())
+		source.SetResult(2)
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]interface{}{1, 2}))
+	})
+	It("returns immediately when no tasks", func() {
+		t := task.WhenAllFailFast()
+		Expect(t.Wait()).To(BeNil())
+	})
+	It("faults on first error and cancels the remaining tasks", func() {
+		started := make(chan struct{})
+		slow := task.RunContextErrAction(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		<-started
+		failing := task.NewCompletionSource()
+		t := task.WhenAllFailFast(slow, failing.Task())
+		expected := fmt.Errorf("error")
+		failing.SetError(expected)
+
+		err := t.Wait()
+		Expect(err).ToNot(BeNil())
+		Expect(t.IsFaulted()).To(BeTrue())
+		Expect(slow.Wait()).ToNot(BeNil())
+		Expect(slow.IsCanceled()).To(BeTrue())
+
+		aggregate, ok := err.(task.AggregateError)
+		Expect(ok).To(BeTrue())
+		Expect(len(aggregate.Errors())).To(Equal(1))
+		taskErr := aggregate.Errors()[0].(task.TaskError)
+		Expect(taskErr.Index()).To(Equal(1))
+		Expect(taskErr.Unwrap()).To(Equal(expected))
+	})
+	It("reports all faults observed", func() {
+		t := task.WhenAllFailFast(task.FromError(fmt.Errorf("first")), task.FromError(fmt.Errorf("second")))
+		err := t.Wait()
+		Expect(err).ToNot(BeNil())
+		Expect(len(err.(task.AggregateError).Errors())).To(Equal(2))
+	})
+	It("is canceled when a task is canceled", func() {
+		source := task.NewCompletionSource()
+		other := task.NewCompletionSource()
+		t := task.WhenAllFailFast(source.Task(), other.Task())
+		source.SetCanceled()
+		Expect(t.Wait()).ToNot(BeNil())
+		Expect(t.IsCanceled()).To(BeTrue())
+		Expect(other.Task().IsCanceled()).To(BeTrue())
+	})
+})
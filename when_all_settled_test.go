@@ -0,0 +1,34 @@
+package task_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("WhenAllSettled", func() {
+	It("returns outcomes in order", func() {
+		expected := fmt.Errorf("error")
+		canceled := task.NewCompletionSource()
+		t := task.WhenAllSettled(task.FromResult(1), task.FromError(expected), canceled.Task())
+		Expect(t.IsCompleted()).To(BeFalse())
+		canceled.SetCanceled()
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.IsSuccess()).To(BeTrue())
+
+		outcomes, ok := t.Result().([]task.Outcome)
+		Expect(ok).To(BeTrue())
+		Expect(outcomes).To(HaveLen(3))
+		Expect(outcomes[0]).To(Equal(task.Outcome{Status: task.StatusSuccess, Result: 1}))
+		Expect(outcomes[1]).To(Equal(task.Outcome{Status: task.StatusFaulted, Err: expected}))
+		Expect(outcomes[2].Status).To(Equal(task.StatusCanceled))
+		Expect(outcomes[2].Err).ToNot(BeNil())
+	})
+	It("returns immediately when no tasks", func() {
+		t := task.WhenAllSettled()
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]task.Outcome{}))
+	})
+})
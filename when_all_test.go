@@ -1,6 +1,7 @@
 package task_test
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -77,4 +78,41 @@ var _ = Describe("WhenAll", func() {
 		Expect(aggregate).ToNot(BeNil())
 		Expect(len(aggregate.Errors())).To(Equal(3))
 	})
+	It("returns results in order", func() {
+		source := task.NewCompletionSource()
+		t := task.WhenAll(source.Task(), task.FromResult(2), task.FromResult(3))
+		source.SetResult(1)
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]interface{}{1, 2, 3}))
+	})
+	It("returns empty results when no tasks", func() {
+		t := task.WhenAll()
+		Expect(t.Result()).To(Equal([]interface{}{}))
+	})
+	It("can wait for the same task twice", func() {
+		source := task.NewCompletionSource()
+		t := task.WhenAll(source.Task(), source.Task())
+		source.SetResult(1)
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]interface{}{1, 1}))
+	})
+	It("attributes errors to task index", func() {
+		expected := fmt.Errorf("error")
+		failed := task.FromError(expected)
+		t := task.WhenAll(task.Completed(), failed)
+		err := t.Wait()
+		Expect(err).ToNot(BeNil())
+		Expect(t.Result()).To(BeNil())
+
+		aggregate, ok := err.(task.AggregateError)
+		Expect(ok).To(BeTrue())
+		Expect(len(aggregate.Errors())).To(Equal(1))
+
+		taskErr, ok := aggregate.Errors()[0].(task.TaskError)
+		Expect(ok).To(BeTrue())
+		Expect(taskErr.Index()).To(Equal(1))
+		Expect(taskErr.Task()).To(Equal(failed))
+		Expect(errors.Is(taskErr, expected)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("task 1: error"))
+	})
 })
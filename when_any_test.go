@@ -1,6 +1,8 @@
 package task_test
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -24,12 +26,80 @@ var _ = Describe("WhenAny", func() {
 		t := task.WhenAny(blocking, completed)
 		Expect(t.Wait()).To(BeNil())
 	})
-	It("returns immediately when no tasks", func() {
+	It("faults immediately when no tasks", func() {
 		t := task.WhenAny()
-		Expect(t.Wait()).To(BeNil())
+		Expect(t.Wait()).To(Equal(task.ErrNoTasks))
+		Expect(t.IsFaulted()).To(BeTrue())
 	})
 	It("can process completed task", func() {
 		t := task.WhenAny(task.Completed())
 		Expect(t.Wait()).To(BeNil())
 	})
+	It("returns first completed task", func() {
+		first := task.NewCompletionSource()
+		second := task.NewCompletionSource()
+		t := task.WhenAny(first.Task(), second.Task())
+		second.SetResult(2)
+		first.SetResult(1)
+		Expect(t.Wait()).To(BeNil())
+
+		result, ok := t.Result().(task.WhenAnyResult)
+		Expect(ok).To(BeTrue())
+		Expect(result.Index()).To(Equal(1))
+		Expect(result.Task()).To(Equal(second.Task()))
+		Expect(result.Task().Result()).To(Equal(2))
+	})
+	It("only inspects the first completed task", func() {
+		source := task.NewCompletionSource()
+		t := task.WhenAny(source.Task(), task.FromError(fmt.Errorf("error")))
+		source.SetResult(1)
+		err := t.Wait()
+		Expect(err).ToNot(BeNil())
+		Expect(t.IsFaulted()).To(BeTrue())
+
+		taskErr, ok := err.(task.TaskError)
+		Expect(ok).To(BeTrue())
+		Expect(taskErr.Index()).To(Equal(1))
+	})
+	It("does not fail when other tasks fail", func() {
+		source := task.NewCompletionSource()
+		t := task.WhenAny(task.FromResult(1), source.Task())
+		source.SetError(fmt.Errorf("error"))
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result().(task.WhenAnyResult).Index()).To(Equal(0))
+	})
+	It("can cancel remaining tasks", func() {
+		first := task.NewCompletionSource()
+		started := make(chan struct{})
+		slow := task.RunContextErrAction(func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		<-started
+		t := task.WhenAnyCancelRemaining(first.Task(), slow)
+		first.SetResult(1)
+		Expect(t.Wait()).To(BeNil())
+		Expect(slow.Wait()).ToNot(BeNil())
+		Expect(slow.IsCanceled()).To(BeTrue())
+		Expect(first.Task().IsSuccess()).To(BeTrue())
+	})
+	It("lets running tasks that ignore cancellation complete", func() {
+		first := task.NewCompletionSource()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		slow := task.RunFunc(func() interface{} {
+			close(started)
+			<-release
+			return 2
+		})
+		<-started
+		t := task.WhenAnyCancelRemaining(first.Task(), slow)
+		first.SetResult(1)
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result().(task.WhenAnyResult).Index()).To(Equal(0))
+		Expect(slow.IsCompleted()).To(BeFalse())
+		close(release)
+		Eventually(slow.Status).Should(Equal(task.StatusSuccess))
+	})
 })
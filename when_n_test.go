@@ -0,0 +1,68 @@
+package task_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/patrickhuber/go-task"
+)
+
+var _ = Describe("WhenN", func() {
+	var (
+		sources []task.CompletionSource
+		tasks   []task.Task
+	)
+	BeforeEach(func() {
+		sources = []task.CompletionSource{}
+		tasks = []task.Task{}
+		for i := 0; i < 3; i++ {
+			source := task.NewCompletionSource()
+			sources = append(sources, source)
+			tasks = append(tasks, source.Task())
+		}
+	})
+	It("succeeds when k tasks succeed", func() {
+		t := task.WhenN(2, tasks...)
+		sources[2].SetResult(2)
+		Expect(t.IsCompleted()).To(BeFalse())
+		sources[0].SetResult(0)
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]interface{}{0, 2}))
+	})
+	It("succeeds with failures below the limit", func() {
+		t := task.WhenN(2, tasks...)
+		sources[0].SetError(fmt.Errorf("error"))
+		sources[1].SetResult(1)
+		sources[2].SetResult(2)
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]interface{}{1, 2}))
+	})
+	It("faults when success is impossible", func() {
+		t := task.WhenN(2, tasks...)
+		sources[0].SetError(fmt.Errorf("error"))
+		Expect(t.IsCompleted()).To(BeFalse())
+		sources[1].SetCanceled()
+		err := t.Wait()
+		Expect(err).ToNot(BeNil())
+		Expect(t.IsFaulted()).To(BeTrue())
+		aggregate, ok := err.(task.AggregateError)
+		Expect(ok).To(BeTrue())
+		Expect(len(aggregate.Errors())).To(Equal(2))
+		Expect(aggregate.Errors()[1].(task.TaskError).Index()).To(Equal(1))
+	})
+	It("faults when k is larger than the number of tasks", func() {
+		t := task.WhenN(4, tasks...)
+		Expect(t.Wait()).To(Equal(task.ErrQuorumUnreachable))
+	})
+	It("succeeds immediately when k is zero", func() {
+		t := task.WhenN(0, tasks...)
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]interface{}{}))
+	})
+	It("can process completed tasks", func() {
+		t := task.WhenN(1, task.FromError(fmt.Errorf("error")), task.FromResult(1))
+		Expect(t.Wait()).To(BeNil())
+		Expect(t.Result()).To(Equal([]interface{}{1}))
+	})
+})